@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -11,12 +12,18 @@ import (
 
 	"github.com/groall/upsource-ai-reviewer/internal/metrics"
 	"github.com/groall/upsource-ai-reviewer/internal/review"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
 	"github.com/groall/upsource-ai-reviewer/pkg/config"
 )
 
 func main() {
 	var configFile string // main configuration file
+	var report, listSuppressions bool
+	var deleteSuppression string
 	flag.StringVar(&configFile, "config", "config.yaml", "path to config file")
+	flag.BoolVar(&report, "report", false, "print the outcomes of posted comments and exit")
+	flag.BoolVar(&listSuppressions, "suppressions", false, "print the stored suppressions and exit")
+	flag.StringVar(&deleteSuppression, "delete-suppression", "", "delete the suppression with this id and exit")
 	flag.Parse()
 
 	var appConfig *config.Config
@@ -31,6 +38,29 @@ func main() {
 		log.Fatalf("Invalid config: %v", err)
 	}
 
+	if report || listSuppressions || deleteSuppression != "" {
+		stateStore, err := store.Open(appConfig.Store.Path)
+		if err != nil {
+			log.Fatalf("Failed to open store: %v", err)
+		}
+
+		switch {
+		case report:
+			err = writeReport(os.Stdout, stateStore.AllFeedback())
+		case listSuppressions:
+			err = writeSuppressions(os.Stdout, stateStore.AllSuppressions(), appConfig.Suppressions.MinRejections)
+		default:
+			var deleted bool
+			if deleted, err = stateStore.DeleteSuppression(deleteSuppression); err == nil && !deleted {
+				err = fmt.Errorf("suppression %s not found", deleteSuppression)
+			}
+		}
+		if err != nil {
+			log.Fatalf("Failed to run admin command: %v", err)
+		}
+		return
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+)
+
+// reportGroups are the sections of the feedback report and how comments are grouped in them.
+var reportGroups = []struct {
+	title string
+	key   func(store.Feedback) string
+}{
+	{title: "Model", key: func(f store.Feedback) string { return f.Model }},
+	{title: "Model / prompt version", key: func(f store.Feedback) string { return f.Model + " / " + f.PromptVersion }},
+	{title: "Severity", key: func(f store.Feedback) string { return f.Severity }},
+	{title: "File type", key: func(f store.Feedback) string { return f.FileType }},
+}
+
+// writeReport prints the outcomes of the bot's comments and the acceptance rates, grouped by model,
+// prompt version, severity and file type.
+func writeReport(w io.Writer, feedback []store.Feedback) error {
+	if len(feedback) == 0 {
+		_, err := fmt.Fprintln(w, "No comment outcomes recorded yet. Enable feedback in the configuration to collect them.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, group := range reportGroups {
+		if i > 0 {
+			_, _ = fmt.Fprintln(tw)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\tcomments\taccepted\tdisagreed\tignored\tresolved without reply\tpending\tacceptance rate\n", group.title)
+
+		for _, s := range store.Summarize(feedback, group.key) {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n", s.Key, s.Classified()+s.Pending,
+				s.Counts[store.OutcomeAccepted], s.Counts[store.OutcomeDisagreed], s.Counts[store.OutcomeIgnored],
+				s.Counts[store.OutcomeResolvedWithoutReply], s.Pending, acceptanceRate(s))
+		}
+	}
+
+	return tw.Flush()
+}
+
+func acceptanceRate(s store.OutcomeSummary) string {
+	if s.Classified() == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.0f%%", s.AcceptanceRate()*100)
+}
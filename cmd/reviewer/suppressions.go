@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+)
+
+// writeSuppressions prints the stored suppressions with their scope and whether they are in effect.
+func writeSuppressions(w io.Writer, suppressions []store.Suppression, minRejections int) error {
+	if len(suppressions) == 0 {
+		_, err := fmt.Fprintln(w, "No suppressions stored.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "id\tproject\tpath\tsource\trejections\tactive\ttext")
+	for _, sup := range suppressions {
+		path, source := sup.Path, "command"
+		if path == "" {
+			path = "*"
+		}
+		if sup.Learned {
+			source = "learned"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%t\t%s\n", sup.ID, sup.ProjectID, path, source, len(sup.Rejections), sup.Active(minRejections), sup.Text)
+	}
+
+	return tw.Flush()
+}
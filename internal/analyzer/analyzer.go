@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+// Finding is a single issue reported by a static analyzer.
+type Finding struct {
+	Tool     string
+	Rule     string
+	FilePath string // Repository-relative path.
+	Line     int
+	Column   int
+	Message  string
+	Severity string // "low", "medium" or "high".
+}
+
+// Runner executes the configured analyzers on a checkout of a review branch.
+type Runner struct {
+	tools   []config.AnalyzerTool
+	timeout time.Duration
+}
+
+// New creates a new Runner instance.
+func New(cfg config.Analyzers) *Runner {
+	return &Runner{
+		tools:   cfg.Tools,
+		timeout: cfg.Timeout,
+	}
+}
+
+// Run executes every configured tool in dir and returns their combined findings.
+// A failing tool is logged and skipped; analyzers exit non-zero whenever they report issues,
+// so the exit status alone is not treated as an error as long as the output can be parsed.
+func (r *Runner) Run(ctx context.Context, dir string) []Finding {
+	var findings []Finding
+	for _, tool := range r.tools {
+		toolFindings, err := r.runTool(ctx, tool, dir)
+		if err != nil {
+			log.Printf("Analyzer %s failed: %v\n", tool.Name, err)
+			continue
+		}
+		log.Printf("Analyzer %s reported %d findings.\n", tool.Name, len(toolFindings))
+		findings = append(findings, toolFindings...)
+	}
+
+	return findings
+}
+
+func (r *Runner) runTool(ctx context.Context, tool config.AnalyzerTool, dir string) ([]Finding, error) {
+	execCtx := ctx
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(execCtx, "bash", "-lc", tool.Command)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if execCtx.Err() != nil {
+		return nil, fmt.Errorf("command timed out: %w", execCtx.Err())
+	}
+
+	output := stdout.String()
+	if tool.Format == config.AnalyzerFormatText {
+		// go vet and friends write diagnostics to stderr.
+		output += "\n" + stderr.String()
+	}
+
+	findings, err := Parse(tool.Format, output)
+	if err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("command failed: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+		return nil, err
+	}
+
+	for i := range findings {
+		findings[i].Tool = tool.Name
+		findings[i].FilePath = relativePath(dir, findings[i].FilePath)
+		if findings[i].Severity == "" {
+			findings[i].Severity = tool.Severity
+		}
+	}
+
+	return findings, nil
+}
+
+// relativePath converts analyzer-reported paths to repository-relative ones.
+func relativePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(dir, path); err == nil {
+			path = rel
+		}
+	}
+
+	return strings.TrimPrefix(filepath.ToSlash(path), "./")
+}
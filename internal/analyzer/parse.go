@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+// Parse converts analyzer output in the given format into findings.
+func Parse(format, output string) ([]Finding, error) {
+	switch format {
+	case config.AnalyzerFormatText:
+		return parseText(output), nil
+	case config.AnalyzerFormatGolangciLint:
+		return parseGolangciLint(output)
+	case config.AnalyzerFormatStaticcheck:
+		return parseStaticcheck(output)
+	case config.AnalyzerFormatSemgrep:
+		return parseSemgrep(output)
+	default:
+		return nil, fmt.Errorf("unsupported analyzer format %q", format)
+	}
+}
+
+// textLine matches the "file:line[:col]: message" convention used by go vet, staticcheck and most compilers.
+var textLine = regexp.MustCompile(`^(.+?\.[A-Za-z0-9]+):(\d+)(?::(\d+))?:\s*(.+)$`)
+
+// textRule matches a trailing rule code such as "(SA4006)".
+var textRule = regexp.MustCompile(`\s*\(([A-Za-z]+[0-9]+)\)$`)
+
+func parseText(output string) []Finding {
+	var findings []Finding
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		matches := textLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if matches == nil {
+			continue
+		}
+
+		line, _ := strconv.Atoi(matches[2])
+		column, _ := strconv.Atoi(matches[3])
+		message := matches[4]
+
+		var rule string
+		if ruleMatches := textRule.FindStringSubmatch(message); ruleMatches != nil {
+			rule = ruleMatches[1]
+			message = strings.TrimSuffix(message, ruleMatches[0])
+		}
+
+		findings = append(findings, Finding{
+			Rule:     rule,
+			FilePath: matches[1],
+			Line:     line,
+			Column:   column,
+			Message:  message,
+		})
+	}
+
+	return findings
+}
+
+type golangciLintReport struct {
+	Issues []struct {
+		FromLinter string `json:"FromLinter"`
+		Text       string `json:"Text"`
+		Severity   string `json:"Severity"`
+		Pos        struct {
+			Filename string `json:"Filename"`
+			Line     int    `json:"Line"`
+			Column   int    `json:"Column"`
+		} `json:"Pos"`
+	} `json:"Issues"`
+}
+
+func parseGolangciLint(output string) ([]Finding, error) {
+	var report golangciLintReport
+	if err := json.Unmarshal([]byte(extractJSONObject(output)), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse golangci-lint JSON output: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		findings = append(findings, Finding{
+			Rule:     issue.FromLinter,
+			FilePath: issue.Pos.Filename,
+			Line:     issue.Pos.Line,
+			Column:   issue.Pos.Column,
+			Message:  issue.Text,
+			Severity: normalizeSeverity(issue.Severity),
+		})
+	}
+
+	return findings, nil
+}
+
+type staticcheckIssue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Location struct {
+		File   string `json:"file"`
+		Line   int    `json:"line"`
+		Column int    `json:"column"`
+	} `json:"location"`
+	Message string `json:"message"`
+}
+
+// parseStaticcheck parses the output of `staticcheck -f json`, which is one JSON object per line.
+func parseStaticcheck(output string) ([]Finding, error) {
+	var findings []Finding
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+
+		var issue staticcheckIssue
+		if err := json.Unmarshal([]byte(line), &issue); err != nil {
+			return nil, fmt.Errorf("failed to parse staticcheck JSON output: %w", err)
+		}
+		findings = append(findings, Finding{
+			Rule:     issue.Code,
+			FilePath: issue.Location.File,
+			Line:     issue.Location.Line,
+			Column:   issue.Location.Column,
+			Message:  issue.Message,
+			Severity: normalizeSeverity(issue.Severity),
+		})
+	}
+
+	return findings, nil
+}
+
+type semgrepReport struct {
+	Results []struct {
+		CheckID string `json:"check_id"`
+		Path    string `json:"path"`
+		Start   struct {
+			Line int `json:"line"`
+			Col  int `json:"col"`
+		} `json:"start"`
+		Extra struct {
+			Message  string `json:"message"`
+			Severity string `json:"severity"`
+		} `json:"extra"`
+	} `json:"results"`
+}
+
+func parseSemgrep(output string) ([]Finding, error) {
+	var report semgrepReport
+	if err := json.Unmarshal([]byte(extractJSONObject(output)), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse semgrep JSON output: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(report.Results))
+	for _, result := range report.Results {
+		findings = append(findings, Finding{
+			Rule:     result.CheckID,
+			FilePath: result.Path,
+			Line:     result.Start.Line,
+			Column:   result.Start.Col,
+			Message:  result.Extra.Message,
+			Severity: normalizeSeverity(result.Extra.Severity),
+		})
+	}
+
+	return findings, nil
+}
+
+// extractJSONObject trims any log noise printed around a single JSON document.
+func extractJSONObject(output string) string {
+	start := strings.Index(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start != -1 && end != -1 && end > start {
+		return output[start : end+1]
+	}
+
+	return output
+}
+
+// normalizeSeverity maps tool specific severities onto low/medium/high.
+// Unknown values return an empty string so the tool's configured default applies.
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "error", "high", "critical":
+		return "high"
+	case "warning", "warn", "medium":
+		return "medium"
+	case "info", "note", "low", "style":
+		return "low"
+	default:
+		return ""
+	}
+}
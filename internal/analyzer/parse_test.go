@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+func TestParseText(t *testing.T) {
+	output := `# example.com/pkg
+pkg/a.go:12:2: printf: fmt.Sprintf format %d has arg s of wrong type string
+pkg/b.go:7: unreachable code
+main.go:3:9: should omit comparison to bool constant (S1002)
+vet: some tool noise`
+
+	findings, err := Parse(config.AnalyzerFormatText, output)
+	require.NoError(t, err)
+	require.Equal(t, []Finding{
+		{FilePath: "pkg/a.go", Line: 12, Column: 2, Message: "printf: fmt.Sprintf format %d has arg s of wrong type string"},
+		{FilePath: "pkg/b.go", Line: 7, Message: "unreachable code"},
+		{FilePath: "main.go", Line: 3, Column: 9, Rule: "S1002", Message: "should omit comparison to bool constant"},
+	}, findings)
+}
+
+func TestParseGolangciLint(t *testing.T) {
+	output := `level=warning msg="noise"
+{"Issues":[{"FromLinter":"errcheck","Text":"Error return value is not checked","Severity":"error","Pos":{"Filename":"a.go","Line":5,"Column":3}}],"Report":{}}`
+
+	findings, err := Parse(config.AnalyzerFormatGolangciLint, output)
+	require.NoError(t, err)
+	require.Equal(t, []Finding{
+		{Rule: "errcheck", FilePath: "a.go", Line: 5, Column: 3, Message: "Error return value is not checked", Severity: "high"},
+	}, findings)
+}
+
+func TestParseStaticcheck(t *testing.T) {
+	output := `{"code":"SA4006","severity":"error","location":{"file":"/src/a.go","line":8,"column":2},"message":"value never used"}
+{"code":"ST1003","severity":"unknown","location":{"file":"/src/b.go","line":1,"column":1},"message":"bad name"}`
+
+	findings, err := Parse(config.AnalyzerFormatStaticcheck, output)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	require.Equal(t, "high", findings[0].Severity)
+	require.Equal(t, "", findings[1].Severity)
+	require.Equal(t, "/src/b.go", findings[1].FilePath)
+}
+
+func TestParseSemgrep(t *testing.T) {
+	output := `{"results":[{"check_id":"go.lang.security.sqli","path":"db/query.go","start":{"line":21,"col":4},"extra":{"message":"SQL built from user input","severity":"WARNING"}}],"errors":[]}`
+
+	findings, err := Parse(config.AnalyzerFormatSemgrep, output)
+	require.NoError(t, err)
+	require.Equal(t, []Finding{
+		{Rule: "go.lang.security.sqli", FilePath: "db/query.go", Line: 21, Column: 4, Message: "SQL built from user input", Severity: "medium"},
+	}, findings)
+}
+
+func TestParseRejectsMalformedJSON(t *testing.T) {
+	_, err := Parse(config.AnalyzerFormatSemgrep, "semgrep crashed")
+	require.ErrorContains(t, err, "failed to parse semgrep JSON output")
+}
+
+func TestRelativePath(t *testing.T) {
+	require.Equal(t, "pkg/a.go", relativePath("/tmp/checkout", "/tmp/checkout/pkg/a.go"))
+	require.Equal(t, "pkg/a.go", relativePath("/tmp/checkout", "./pkg/a.go"))
+}
@@ -1,5 +1,7 @@
 package git
 
+import "context"
+
 type Review interface {
 	GetDefaultBranch() string
 	GetBranch() string
@@ -9,3 +11,8 @@ type Review interface {
 type Provider interface {
 	GetReviewChanges(review Review) (string, string, error)
 }
+
+// Checkouter is implemented by providers that can clone the review branch to a local directory.
+type Checkouter interface {
+	Checkout(ctx context.Context, review Review, dir string) error
+}
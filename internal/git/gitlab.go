@@ -1,7 +1,12 @@
 package git
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"net/url"
+	"os/exec"
+	"path"
 	"strings"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
@@ -12,6 +17,8 @@ import (
 // GitlabProvider implements the Provider interface for GitLab.
 type GitlabProvider struct {
 	gitlabClient *gitlab.Client
+	baseURL      string
+	accessToken  string
 }
 
 // NewGitlabProvider creates a new GitlabProvider instance.
@@ -23,6 +30,8 @@ func NewGitlabProvider(cfg *config.Config) (*GitlabProvider, error) {
 
 	return &GitlabProvider{
 		gitlabClient: gitlabClient,
+		baseURL:      cfg.Gitlab.BaseURL,
+		accessToken:  cfg.Gitlab.AccessToken,
 	}, nil
 }
 
@@ -95,3 +104,36 @@ func createCommitsCommentsText(commits []*gitlab.Commit) string {
 
 	return commentsBuilder.String()
 }
+
+// Checkout makes a shallow clone of the review branch into dir.
+func (g *GitlabProvider) Checkout(ctx context.Context, review Review, dir string) error {
+	namespace, repoName := review.GetGitNamespaceAndName()
+	cloneURL, err := g.cloneURL(namespace, repoName)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--quiet", "--depth", "1", "--branch", review.GetBranch(), cloneURL, dir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// The clone URL carries the access token, so never echo it back.
+		msg := strings.ReplaceAll(stderr.String(), g.accessToken, "***")
+		return fmt.Errorf("failed to clone %s/%s at %s: %w: %s", namespace, repoName, review.GetBranch(), err, strings.TrimSpace(msg))
+	}
+
+	return nil
+}
+
+// cloneURL builds an authenticated HTTPS clone URL for the given project.
+func (g *GitlabProvider) cloneURL(namespace, repoName string) (string, error) {
+	u, err := url.Parse(g.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse gitlab base url: %w", err)
+	}
+
+	u.User = url.UserPassword("oauth2", g.accessToken)
+	u.Path = path.Join("/", u.Path, namespace, repoName+".git")
+
+	return u.String(), nil
+}
@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// AnchorResolver loads the file a discussion is anchored to.
+type AnchorResolver interface {
+	ResolveFileAnchor(review *upsource.Review, anchor client.AnchorDTO) (*upsource.FileAnchor, error)
+}
+
+// anchorContextLines is how many lines around the anchored range are shown to the LLM.
+const anchorContextLines = 15
+
+// anchoredContext is the code context of a discussion.
+type anchoredContext struct {
+	code   string
+	anchor string // Description of the anchor, empty for general discussions.
+	// file is the anchored file, nil for general discussions and anchors that could not be resolved.
+	file *upsource.FileAnchor
+}
+
+// anchoredCodeContext returns the code context for a discussion. For file discussions the context
+// is the anchored lines at the commented revision and at the review head plus the diff of that file;
+// general discussions get the whole review diff. When the anchor cannot be resolved, the diff of the file is used.
+func (rr *ReviewReplier) anchoredCodeContext(d client.DiscussionInFileDTO) (*anchoredContext, error) {
+	diff, err := rr.loadCodeContext()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Anchor.FileID == "" {
+		return &anchoredContext{code: diff}, nil
+	}
+
+	path := strings.TrimPrefix(d.Anchor.FileID, "/")
+	fd := fileDiff(diff, path)
+
+	if rr.replier.anchors != nil {
+		fa, err := rr.replier.anchors.ResolveFileAnchor(rr.review, d.Anchor)
+		if err == nil {
+			return &anchoredContext{code: formatFileAnchor(fa, fd), anchor: describeFileAnchor(fa), file: fa}, nil
+		}
+		log.Printf("Failed to resolve anchor of discussion %s, using the diff: %v\n", d.DiscussionID, err)
+	}
+
+	if fd == "" {
+		fd = diff
+	}
+
+	return &anchoredContext{code: fd, anchor: buildReplyAnchorText(d.Anchor)}, nil
+}
+
+// describeFileAnchor names the file and lines a discussion is about, e.g. "main.go, lines 10-12".
+func describeFileAnchor(fa *upsource.FileAnchor) string {
+	switch {
+	case fa.StartLine == 0:
+		return fa.File
+	case fa.EndLine > fa.StartLine:
+		return fmt.Sprintf("%s, lines %d-%d", fa.File, fa.StartLine, fa.EndLine)
+	default:
+		return fmt.Sprintf("%s, line %d", fa.File, fa.StartLine)
+	}
+}
+
+// formatFileAnchor renders the anchored lines at the commented revision and at the review head
+// with line numbers, followed by the diff of the file.
+func formatFileAnchor(fa *upsource.FileAnchor, fd string) string {
+	var b strings.Builder
+
+	_, _ = fmt.Fprintf(&b, "#### %s at the commented revision %s\n\n", fa.File, fa.RevisionID)
+	b.WriteString(excerpt(fa.Content, fa.StartLine, fa.EndLine))
+
+	switch {
+	case fa.HeadRevisionID == "":
+		b.WriteString("\n#### Latest revision\n\nThe file is not part of the latest review changes.\n")
+	case fa.HeadRevisionID == fa.RevisionID:
+		b.WriteString("\n#### Latest revision\n\nThe file has not changed since the comment.\n")
+	default:
+		start, end, found := locateLines(fa.Content, fa.HeadContent, fa.StartLine, fa.EndLine)
+		_, _ = fmt.Fprintf(&b, "\n#### %s at the latest revision %s\n\n", fa.File, fa.HeadRevisionID)
+		if !found {
+			b.WriteString("The commented lines were changed or removed; showing the same line numbers.\n\n")
+		}
+		b.WriteString(excerpt(fa.HeadContent, start, end))
+	}
+
+	_, _ = fmt.Fprintf(&b, "\n#### Diff of %s\n\n", fa.File)
+	if fd == "" {
+		b.WriteString("The file is not changed in the review diff.\n")
+	} else {
+		b.WriteString(fd)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// excerpt returns the lines start..end of text with anchorContextLines lines around them, numbered,
+// marking the lines of the range with ">". Without a range the beginning of the file is shown.
+func excerpt(text string, start, end int) string {
+	lines := strings.Split(text, "\n")
+	if start <= 0 {
+		start, end = 1, 0
+	}
+
+	from := max(start-anchorContextLines, 1)
+	to := min(max(end, start)+anchorContextLines, len(lines))
+
+	var b strings.Builder
+	b.WriteString("```\n")
+	for line := from; line <= to; line++ {
+		marker := " "
+		if line >= start && line <= end {
+			marker = ">"
+		}
+		_, _ = fmt.Fprintf(&b, "%s%5d  %s\n", marker, line, lines[line-1])
+	}
+	b.WriteString("```\n")
+
+	return b.String()
+}
+
+// locateLines finds the lines start..end of oldText in newText by the first non-blank anchored line,
+// preferring the match closest to the old position. Without a match the old line numbers are returned.
+func locateLines(oldText, newText string, start, end int) (int, int, bool) {
+	oldLines := strings.Split(oldText, "\n")
+	if start <= 0 || start > len(oldLines) {
+		return start, end, false
+	}
+
+	offset, target := 0, ""
+	for line := start; line <= max(end, start) && line <= len(oldLines); line++ {
+		if target = normalizeCodeLine(oldLines[line-1]); target != "" {
+			offset = line - start
+			break
+		}
+	}
+	if target == "" {
+		return start, end, false
+	}
+
+	bestLine := 0
+	for i, text := range strings.Split(newText, "\n") {
+		line := i + 1
+		if normalizeCodeLine(text) != target {
+			continue
+		}
+		if bestLine == 0 || closer(line-offset, bestLine, start) {
+			bestLine = line - offset
+		}
+	}
+	if bestLine == 0 {
+		return start, end, false
+	}
+
+	return bestLine, end + bestLine - start, true
+}
@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+	"github.com/groall/upsource-go-client/client"
+)
+
+func numberedLines(n int, edit func(line int) string) string {
+	lines := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		lines = append(lines, edit(i))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestLocateLines(t *testing.T) {
+	old := numberedLines(40, func(line int) string { return "line" + strings.Repeat("x", line) })
+	// Three lines inserted at the top move every line down.
+	moved := "a\nb\nc\n" + old
+
+	start, end, found := locateLines(old, moved, 10, 12)
+	require.True(t, found)
+	require.Equal(t, 13, start)
+	require.Equal(t, 15, end)
+
+	start, end, found = locateLines(old, "rewritten", 10, 12)
+	require.False(t, found)
+	require.Equal(t, 10, start)
+	require.Equal(t, 12, end)
+}
+
+func TestExcerpt(t *testing.T) {
+	text := numberedLines(40, func(line int) string { return "code" })
+
+	got := excerpt(text, 20, 21)
+	require.True(t, strings.HasPrefix(got, "```\n     5  code\n"))
+	require.Contains(t, got, ">   20  code\n>   21  code\n    22  code\n")
+	require.True(t, strings.HasSuffix(got, "    36  code\n```\n"))
+
+	require.True(t, strings.HasPrefix(excerpt(text, 0, 0), "```\n     1  code\n"))
+}
+
+type fakeAnchorResolver struct {
+	anchor *upsource.FileAnchor
+	err    error
+}
+
+func (f *fakeAnchorResolver) ResolveFileAnchor(*upsource.Review, client.AnchorDTO) (*upsource.FileAnchor, error) {
+	return f.anchor, f.err
+}
+
+func TestAnchoredCodeContext(t *testing.T) {
+	reviewer := &Reviewer{gitProvider: &replierMockGitProvider{changes: mentionTestDiff}, ctx: context.Background()}
+	fileDiscussion := client.DiscussionInFileDTO{Anchor: client.AnchorDTO{FileID: "/b.go", RevisionID: "r1"}}
+
+	t.Run("file discussion", func(t *testing.T) {
+		resolver := &fakeAnchorResolver{anchor: &upsource.FileAnchor{
+			File: "b.go", RevisionID: "r1", StartLine: 1, EndLine: 1, Content: "package b",
+			HeadRevisionID: "r2", HeadContent: "package bb",
+		}}
+		rr := mustNewReplier(t, reviewer, ReplyConfig{}, resolver).ForReview(&upsource.Review{})
+
+		ac, err := rr.anchoredCodeContext(fileDiscussion)
+		require.NoError(t, err)
+		require.Equal(t, "b.go, line 1", ac.anchor)
+		require.Same(t, resolver.anchor, ac.file)
+		require.Equal(t, "#### b.go at the commented revision r1\n\n```\n>    1  package b\n```\n"+
+			"\n#### b.go at the latest revision r2\n\n"+
+			"The commented lines were changed or removed; showing the same line numbers.\n\n```\n>    1  package bb\n```\n"+
+			"\n#### Diff of b.go\n\n--- a/b.go\n+++ b/b.go\n@@ -1,1 +1,1 @@\n-package b\n+package bb\n", ac.code)
+	})
+
+	t.Run("unresolved anchor falls back to the file diff", func(t *testing.T) {
+		rr := mustNewReplier(t, reviewer, ReplyConfig{}, &fakeAnchorResolver{err: errors.New("not found")}).ForReview(&upsource.Review{})
+
+		ac, err := rr.anchoredCodeContext(fileDiscussion)
+		require.NoError(t, err)
+		require.Equal(t, fileDiff(mentionTestDiff, "b.go"), ac.code)
+		require.Nil(t, ac.file)
+	})
+
+	t.Run("general discussion gets the whole diff", func(t *testing.T) {
+		rr := mustNewReplier(t, reviewer, ReplyConfig{}, &fakeAnchorResolver{}).ForReview(&upsource.Review{})
+
+		ac, err := rr.anchoredCodeContext(client.DiscussionInFileDTO{})
+		require.NoError(t, err)
+		require.Equal(t, mentionTestDiff, ac.code)
+		require.Empty(t, ac.anchor)
+	})
+}
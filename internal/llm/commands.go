@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"github.com/groall/upsource-go-client/client"
+)
+
+const explainSystemMessage = `You are an AI code reviewer. A developer asked you to explain the code or the review comment of a code review discussion.
+
+Explain what the anchored code does and, when the discussion raises an issue, why it matters and how it could be addressed.
+
+Reply ONLY with a JSON object:
+
+{
+  "comment": "<explanation>",
+  "close": false
+}
+
+Rules:
+
+- Write ` + "`comment`" + ` in the same language as the discussion.
+- Keep the explanation concise and specific to the code being discussed.`
+
+const fixSystemMessage = `You are an AI code reviewer. A developer asked you to propose a fix for the code of a code review discussion.
+
+Propose a minimal patch for the anchored lines that addresses the issue raised in the discussion.
+
+Reply ONLY with a JSON object:
+
+{
+  "comment": "<one sentence describing the fix, followed by the patch as a fenced diff block>",
+  "close": false
+}
+
+Rules:
+
+- The patch must be a unified diff of the anchored file with correct context lines.
+- Change only what is needed to address the issue.
+- If the discussion does not describe a problem that can be fixed in this file, say so in ` + "`comment`" + ` instead of a patch.`
+
+// Explain asks the LLM to explain the code or finding a discussion is about.
+func (rr *ReviewReplier) Explain(d client.DiscussionInFileDTO, botUserID string) (string, error) {
+	return rr.commandReply(d, botUserID, rr.replier.templates.explain)
+}
+
+// Fix asks the LLM for a patch of the lines a discussion is anchored to.
+func (rr *ReviewReplier) Fix(d client.DiscussionInFileDTO, botUserID string) (string, error) {
+	return rr.commandReply(d, botUserID, rr.replier.templates.fix)
+}
+
+func (rr *ReviewReplier) commandReply(d client.DiscussionInFileDTO, botUserID string, systemMessage *replyTemplate) (string, error) {
+	ac, err := rr.anchoredCodeContext(d)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := rr.reply(d, botUserID, systemMessage, ac.code, ac.anchor)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Comment, nil
+}
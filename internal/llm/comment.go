@@ -1,12 +1,31 @@
 package llm
 
+import (
+	"log"
+	"strings"
+)
+
 // ReviewComment matches the JSON structure we requested from the LLM.
 type ReviewComment struct {
-	FilePath     string `json:"filePath"`     // Path to the file where the comment is made.
-	LineNumber   int    `json:"lineNumber"`   // Line number in the file where the comment is made.
-	LineVerified bool   `json:"lineVerified"` // Whether the line in the file is verified or not.
-	Comment      string `json:"comment"`      // The actual comment text.
-	Severity     string `json:"severity"`     // Severity of the comment, can be "low", "medium", or "high".
+	FilePath      string      `json:"filePath"`                // Path to the file where the comment is made.
+	LineNumber    int         `json:"lineNumber"`              // Line number in the file where the comment is made.
+	EndLineNumber int         `json:"endLineNumber,omitempty"` // Last line of a multi-line comment; 0 anchors the comment to LineNumber only.
+	Side          string      `json:"side,omitempty"`          // Side of the diff the lines refer to: "new" (default) or "old" for removed lines.
+	LineVerified  bool        `json:"lineVerified"`            // Whether the line in the file is verified or not.
+	Comment       string      `json:"comment"`                 // The actual comment text.
+	Severity      string      `json:"severity"`                // Severity of the comment, can be "low", "medium", or "high".
+	Confidence    float64     `json:"confidence"`              // Confidence that the comment is valid, from 0 to 1; 0 means unknown.
+	Snippet       string      `json:"snippet,omitempty"`       // The exact code the comment is about, used to correct the line number.
+	ReportedLine  int         `json:"-"`                       // The line reported by the model when it is not a changed line; LineNumber is 0 then.
+	Suggestion    *Suggestion `json:"suggestion,omitempty"`    // Optional replacement for a range of added lines.
+}
+
+// Suggestion is a concrete fix: the lines StartLine..EndLine of the new file are replaced with NewText.
+type Suggestion struct {
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	NewText   string `json:"newText"`
+	OldText   string `json:"-"` // The replaced lines, filled in from the diff during validation.
 }
 
 const (
@@ -14,3 +33,58 @@ const (
 	SeverityMedium = "medium"
 	SeverityHigh   = "high"
 )
+
+const (
+	SideNew = "new"
+	SideOld = "old"
+)
+
+// SeverityRank orders severities from the most to the least important: high is 0, unknown values sort last.
+func SeverityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case SeverityHigh:
+		return 0
+	case SeverityMedium:
+		return 1
+	case SeverityLow:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// normalizeComments lowercases severities, downgrades unknown ones to low, defaults the side to new and brings
+// confidences into the 0..1 range (models sometimes answer in percent).
+func normalizeComments(comments []*ReviewComment) []*ReviewComment {
+	for _, c := range comments {
+		c.Severity = strings.ToLower(strings.TrimSpace(c.Severity))
+		if SeverityRank(c.Severity) > 2 {
+			log.Printf("Unknown severity %q for comment on %s:%d, treating it as %s\n", c.Severity, c.FilePath, c.LineNumber, SeverityLow)
+			c.Severity = SeverityLow
+		}
+
+		c.Side = strings.ToLower(strings.TrimSpace(c.Side))
+		if c.Side != SideOld {
+			c.Side = SideNew
+		}
+
+		c.Confidence = normalizeConfidence(c.Confidence)
+	}
+
+	return comments
+}
+
+// normalizeConfidence brings a confidence reported by the model into the 0..1 range.
+// Values above 1 are read as percent.
+func normalizeConfidence(confidence float64) float64 {
+	switch {
+	case confidence < 0:
+		return 0
+	case confidence > 1 && confidence <= 100:
+		return confidence / 100
+	case confidence > 100:
+		return 1
+	}
+
+	return confidence
+}
@@ -0,0 +1,23 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeComments(t *testing.T) {
+	comments := normalizeComments([]*ReviewComment{
+		{Severity: " HIGH ", Confidence: 0.8},
+		{Severity: "critical", Confidence: 85},
+		{Severity: "medium", Confidence: -1},
+		{Severity: "low", Confidence: 250},
+	})
+
+	require.Equal(t, SeverityHigh, comments[0].Severity)
+	require.Equal(t, 0.8, comments[0].Confidence)
+	require.Equal(t, SeverityLow, comments[1].Severity)
+	require.InDelta(t, 0.85, comments[1].Confidence, 1e-9)
+	require.Equal(t, float64(0), comments[2].Confidence)
+	require.Equal(t, float64(1), comments[3].Confidence)
+}
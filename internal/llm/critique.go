@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+const defaultCritiqueSystemMessage = `You are a senior engineer verifying draft code review comments written by another reviewer.
+
+For every draft comment decide, using the diff:
+* valid: the described problem really exists in the changed code,
+* actionable: the author can do something concrete about it,
+* severity: the severity you would assign ("low", "medium" or "high"),
+* confidence: how sure you are that the comment is valid and worth posting (0.0 - 1.0).
+
+Respond ONLY with a JSON array, one object per draft comment:
+[
+  {"index": <draft index>, "valid": true|false, "actionable": true|false, "severity": "low"|"medium"|"high", "confidence": <0.0-1.0>, "reason": "<short reason>"}
+]`
+
+const critiqueUserPromptTemplate = `### Diff:
+
+%s
+
+### Draft review comments:
+
+%s
+`
+
+// critiqueVerdict is the critique model's judgement of a single draft comment.
+type critiqueVerdict struct {
+	Index      int     `json:"index"`
+	Valid      bool    `json:"valid"`
+	Actionable bool    `json:"actionable"`
+	Severity   string  `json:"severity"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason"`
+}
+
+// critic runs the self-critique pass over draft review comments.
+type critic struct {
+	llmProvider    Provider
+	cfg            config.Critique
+	activeProvider string
+}
+
+func newCritic(llmProvider Provider, cfg config.Critique, activeProvider string) *critic {
+	if cfg.SystemMessage == "" {
+		cfg.SystemMessage = defaultCritiqueSystemMessage
+	}
+
+	return &critic{
+		llmProvider:    llmProvider,
+		cfg:            cfg,
+		activeProvider: activeProvider,
+	}
+}
+
+// Critique asks the critique model to judge the draft comments and drops or downgrades
+// the ones below the configured bar. On failure the draft comments are returned unchanged.
+func (c *critic) Critique(diff string, comments []*ReviewComment) []*ReviewComment {
+	if len(comments) == 0 {
+		return comments
+	}
+
+	drafts, err := json.Marshal(indexedDrafts(comments))
+	if err != nil {
+		log.Printf("Skipping critique: failed to encode draft comments: %v\n", err)
+		return comments
+	}
+
+	log.Print("Sending critique prompt to LLM...")
+
+	response, err := c.llmProvider.Completion(fmt.Sprintf(critiqueUserPromptTemplate, diff, drafts), c.cfg.SystemMessage)
+	if err != nil {
+		metrics.DefaultRecorder.RecordLLMError(metrics.OperationCritique, c.activeProvider)
+		log.Printf("Skipping critique: LLM request failed: %v\n", err)
+		return comments
+	}
+
+	var verdicts []critiqueVerdict
+	if err := json.Unmarshal([]byte(parseLLMReviewComments(response)), &verdicts); err != nil {
+		log.Printf("Skipping critique: failed to parse LLM response: %v\n", err)
+		return comments
+	}
+
+	return applyCritique(comments, verdicts, c.cfg.MinConfidence)
+}
+
+type indexedDraft struct {
+	Index int `json:"index"`
+	*ReviewComment
+}
+
+func indexedDrafts(comments []*ReviewComment) []indexedDraft {
+	drafts := make([]indexedDraft, 0, len(comments))
+	for i, comment := range comments {
+		drafts = append(drafts, indexedDraft{Index: i, ReviewComment: comment})
+	}
+
+	return drafts
+}
+
+// applyCritique drops comments judged invalid, not actionable or below minConfidence,
+// lowers the severity of the rest when the critique rates them lower and records the critique's confidence.
+// Comments without a verdict are kept as they are.
+func applyCritique(comments []*ReviewComment, verdicts []critiqueVerdict, minConfidence float64) []*ReviewComment {
+	byIndex := make(map[int]critiqueVerdict, len(verdicts))
+	for _, v := range verdicts {
+		byIndex[v.Index] = v
+	}
+
+	kept := make([]*ReviewComment, 0, len(comments))
+	for i, comment := range comments {
+		verdict, ok := byIndex[i]
+		if !ok {
+			kept = append(kept, comment)
+			continue
+		}
+
+		verdict.Confidence = normalizeConfidence(verdict.Confidence)
+		if !verdict.Valid || !verdict.Actionable || verdict.Confidence < minConfidence {
+			log.Printf("Critique dropped comment on %s:%d (valid=%t actionable=%t confidence=%.2f): %s\n",
+				comment.FilePath, comment.LineNumber, verdict.Valid, verdict.Actionable, verdict.Confidence, verdict.Reason)
+			continue
+		}
+
+		severity := strings.ToLower(verdict.Severity)
+		if SeverityRank(severity) < 3 && SeverityRank(severity) > SeverityRank(comment.Severity) {
+			log.Printf("Critique downgraded comment on %s:%d from %s to %s: %s\n",
+				comment.FilePath, comment.LineNumber, comment.Severity, severity, verdict.Reason)
+			comment.Severity = severity
+		}
+		comment.Confidence = verdict.Confidence
+
+		kept = append(kept, comment)
+	}
+
+	return kept
+}
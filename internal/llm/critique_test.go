@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+func TestCritiqueDropsAndDowngradesComments(t *testing.T) {
+	comments := []*ReviewComment{
+		{FilePath: "a.go", LineNumber: 1, Comment: "nil deref", Severity: SeverityHigh},
+		{FilePath: "a.go", LineNumber: 2, Comment: "naming nit", Severity: SeverityMedium},
+		{FilePath: "a.go", LineNumber: 3, Comment: "unsure", Severity: SeverityHigh},
+		{FilePath: "a.go", LineNumber: 4, Comment: "wrong claim", Severity: SeverityHigh},
+		{FilePath: "a.go", LineNumber: 5, Comment: "no verdict", Severity: SeverityLow},
+	}
+
+	var gotSystem string
+	provider := &mockProvider{CompletionFunc: func(userPrompt, systemPrompt string) (string, error) {
+		gotSystem = systemPrompt
+		require.Contains(t, userPrompt, `"index":4`)
+		return "```json\n" + `[
+			{"index": 0, "valid": true, "actionable": true, "severity": "high", "confidence": 0.9},
+			{"index": 1, "valid": true, "actionable": true, "severity": "low", "confidence": 0.8},
+			{"index": 2, "valid": true, "actionable": true, "severity": "high", "confidence": 0.3},
+			{"index": 3, "valid": false, "actionable": true, "severity": "high", "confidence": 0.9}
+		]` + "\n```", nil
+	}}
+
+	got := newCritic(provider, config.Critique{MinConfidence: 0.5}, config.ProviderOpenAI).Critique("diff", comments)
+
+	require.Equal(t, defaultCritiqueSystemMessage, gotSystem)
+	require.Len(t, got, 3)
+	require.Equal(t, "nil deref", got[0].Comment)
+	require.Equal(t, "naming nit", got[1].Comment)
+	require.Equal(t, SeverityLow, got[1].Severity)
+	require.Equal(t, "no verdict", got[2].Comment)
+}
+
+func TestApplyCritiqueNormalizesConfidence(t *testing.T) {
+	comments := []*ReviewComment{
+		{FilePath: "a.go", LineNumber: 1, Comment: "percent", Severity: SeverityHigh},
+		{FilePath: "a.go", LineNumber: 2, Comment: "negative", Severity: SeverityHigh},
+		{FilePath: "a.go", LineNumber: 3, Comment: "too high", Severity: SeverityHigh},
+	}
+	verdicts := []critiqueVerdict{
+		{Index: 0, Valid: true, Actionable: true, Severity: SeverityHigh, Confidence: 85},
+		{Index: 1, Valid: true, Actionable: true, Severity: SeverityHigh, Confidence: -1},
+		{Index: 2, Valid: true, Actionable: true, Severity: SeverityHigh, Confidence: 250},
+	}
+
+	got := applyCritique(comments, verdicts, 0.5)
+
+	require.Len(t, got, 2)
+	require.Equal(t, "percent", got[0].Comment)
+	require.InDelta(t, 0.85, got[0].Confidence, 1e-9)
+	require.Equal(t, "too high", got[1].Comment)
+	require.Equal(t, 1.0, got[1].Confidence)
+}
+
+func TestCritiqueKeepsDraftsWhenLLMFails(t *testing.T) {
+	comments := []*ReviewComment{{FilePath: "a.go", LineNumber: 1, Comment: "c", Severity: SeverityHigh}}
+	provider := &mockProvider{CompletionFunc: func(userPrompt, systemPrompt string) (string, error) {
+		return "", errors.New("boom")
+	}}
+
+	got := newCritic(provider, config.Critique{MinConfidence: 0.5}, config.ProviderOpenAI).Critique("diff", comments)
+	require.Equal(t, comments, got)
+}
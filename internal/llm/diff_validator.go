@@ -1,98 +1,252 @@
 package llm
 
 import (
+	"log"
 	"regexp"
 	"strings"
 )
 
 // validateCommentsAgainstDiff checks whether each LLM-reported location points at
-// an added line in the provided unified diff. Comments that cannot be placed
-// inline are downgraded by clearing LineVerified and zeroing LineNumber.
+// an added line in the provided unified diff, or at a removed line for comments on the old side,
+// relocating comments by their snippet first.
+// Comments that cannot be placed inline are downgraded by clearing LineVerified and zeroing LineNumber;
+// the line the model reported is kept in ReportedLine.
 func validateCommentsAgainstDiff(diff string, comments []*ReviewComment) []*ReviewComment {
 	if diff == "" || len(comments) == 0 {
 		return comments
 	}
 
-	fileLines := buildNewFileLineIndex(diff)
+	addedText := buildLineTextIndex(diff, forEachAddedLine)
+	removedText := buildLineTextIndex(diff, forEachRemovedLine)
 
 	for _, c := range comments {
+		path := normalizeDiffPath(c.FilePath)
+
+		lines := addedText[path]
+		if c.Side == SideOld {
+			lines = removedText[path]
+			if c.Suggestion != nil {
+				log.Printf("Dropping suggestion for removed lines of %s\n", c.FilePath)
+				c.Suggestion = nil
+			}
+		} else {
+			validateSuggestion(c, lines)
+		}
+		relocateBySnippet(c, lines)
+
 		c.LineVerified = false
 		if c.LineNumber <= 0 {
 			c.LineNumber = 0
+			c.EndLineNumber = 0
 			continue
 		}
 
-		path := normalizeDiffPath(c.FilePath)
-		if lines, ok := fileLines[path]; ok {
-			if lines[c.LineNumber] {
-				c.LineVerified = true
-				continue
-			}
+		if _, ok := lines[c.LineNumber]; ok {
+			c.LineVerified = true
+			validateEndLine(c, lines)
+			continue
 		}
 
 		// Not found in diff; set to 0 to indicate unknown.
+		c.ReportedLine = c.LineNumber
 		c.LineNumber = 0
+		c.EndLineNumber = 0
 	}
 
 	return comments
 }
 
+// validateEndLine keeps a multi-line range only when every line of it is a changed line
+// on the comment's side; otherwise the comment falls back to its start line.
+func validateEndLine(c *ReviewComment, changedLines map[int]string) {
+	if c.EndLineNumber <= c.LineNumber {
+		c.EndLineNumber = 0
+		return
+	}
+
+	for line := c.LineNumber + 1; line <= c.EndLineNumber; line++ {
+		if _, ok := changedLines[line]; !ok {
+			log.Printf("Range %s:%d-%d is partly outside the diff, anchoring to line %d\n", c.FilePath, c.LineNumber, c.EndLineNumber, c.LineNumber)
+			c.EndLineNumber = 0
+			return
+		}
+	}
+}
+
+// validateSuggestion keeps a comment's suggestion only when the replaced range lies within
+// the added lines of the file and fills in the replaced text.
+func validateSuggestion(c *ReviewComment, addedLines map[int]string) {
+	s := c.Suggestion
+	if s == nil {
+		return
+	}
+
+	if s.EndLine == 0 {
+		s.EndLine = s.StartLine
+	}
+
+	if s.StartLine <= 0 || s.EndLine < s.StartLine {
+		log.Printf("Dropping suggestion with invalid range %d-%d for %s\n", s.StartLine, s.EndLine, c.FilePath)
+		c.Suggestion = nil
+		return
+	}
+
+	oldLines := make([]string, 0, s.EndLine-s.StartLine+1)
+	for line := s.StartLine; line <= s.EndLine; line++ {
+		text, ok := addedLines[line]
+		if !ok {
+			log.Printf("Dropping suggestion for %s:%d-%d: line %d is not an added line\n", c.FilePath, s.StartLine, s.EndLine, line)
+			c.Suggestion = nil
+			return
+		}
+		oldLines = append(oldLines, text)
+	}
+	s.OldText = strings.Join(oldLines, "\n")
+}
+
 func buildNewFileLineIndex(diff string) map[string]map[int]bool {
 	result := make(map[string]map[int]bool)
 
-	var currentFile string
-	var newLine int
+	forEachAddedLine(diff, func(file string, line int, _ string) {
+		// Only added lines are considered safe targets for inline comments.
+		if _, ok := result[file]; !ok {
+			result[file] = make(map[int]bool)
+		}
+		result[file][line] = true
+	})
+
+	return result
+}
+
+// buildLineTextIndex maps files to the contents of the lines reported by forEach by line number.
+func buildLineTextIndex(diff string, forEach func(diff string, fn func(file string, line int, text string))) map[string]map[int]string {
+	result := make(map[string]map[int]string)
+
+	forEach(diff, func(file string, line int, text string) {
+		if _, ok := result[file]; !ok {
+			result[file] = make(map[int]string)
+		}
+		result[file][line] = text
+	})
+
+	return result
+}
+
+// forEachAddedLine walks a unified diff and calls fn for every added line with its
+// repository-relative file path, its line number in the new file and its content.
+func forEachAddedLine(diff string, fn func(file string, line int, text string)) {
+	walkDiff(diff, func(l diffLine) {
+		if l.kind == '+' {
+			fn(l.file, l.newLine, l.text)
+		}
+	})
+}
+
+// forEachRemovedLine walks a unified diff and calls fn for every removed line with its
+// repository-relative file path, its line number in the old file and its content.
+// Lines of deleted files are reported under their old path.
+func forEachRemovedLine(diff string, fn func(file string, line int, text string)) {
+	walkDiff(diff, func(l diffLine) {
+		if l.kind == '-' {
+			fn(l.file, l.oldLine, l.text)
+		}
+	})
+}
+
+// diffLine is a single hunk line of a unified diff.
+type diffLine struct {
+	file    string
+	kind    byte // '+', '-' or ' '.
+	oldLine int  // Line number in the old file; meaningful for '-' and ' ' lines.
+	newLine int  // Line number in the new file; meaningful for '+' and ' ' lines.
+	text    string
+}
+
+// Hunk headers carry the starting line numbers for both sides of the diff.
+var hunkHeader = regexp.MustCompile(`@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
 
-	// Hunk headers carry the starting line number for the new side of the diff.
-	hunkHeader := regexp.MustCompile(`@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+// walkDiff calls fn for every hunk line of a unified diff. Hunks end after the line counts
+// from their header, so file headers of concatenated diffs are not mistaken for hunk lines.
+func walkDiff(diff string, fn func(l diffLine)) {
+	var oldFile, newFile string
+	var oldLine, newLine int
+	var oldLeft, newLeft int
+	var inHunk bool
 
 	lines := strings.Split(diff, "\n")
-	for _, line := range lines {
+	for i, line := range lines {
+		// A ---/+++ pair starts the next file even when the previous hunk is shorter than its header claims.
+		isFileHeader := strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ")
+
 		switch {
 		case strings.HasPrefix(line, "diff --git "):
-			currentFile = ""
-			newLine = 0
-		case strings.HasPrefix(line, "+++ "):
-			path := strings.TrimSpace(strings.TrimPrefix(line, "+++ "))
-			if path == "/dev/null" {
-				currentFile = ""
-				newLine = 0
-				continue
-			}
-			currentFile = normalizeDiffPath(path)
+			oldFile, newFile = "", ""
+			inHunk = false
+		case isFileHeader || !inHunk && strings.HasPrefix(line, "--- "):
+			inHunk = false
+			oldFile = diffFilePath(strings.TrimPrefix(line, "--- "))
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			newFile = diffFilePath(strings.TrimPrefix(line, "+++ "))
 		case strings.HasPrefix(line, "@@ "):
-			if currentFile == "" {
-				continue
-			}
 			matches := hunkHeader.FindStringSubmatch(line)
-			if len(matches) == 2 {
-				newLine = parseInt(matches[1])
+			inHunk = len(matches) == 5 && (oldFile != "" || newFile != "")
+			if inHunk {
+				oldLine, oldLeft = parseInt(matches[1]), hunkLineCount(matches[2])
+				newLine, newLeft = parseInt(matches[3]), hunkLineCount(matches[4])
 			}
 		default:
-			if currentFile == "" || newLine == 0 {
+			if !inHunk || len(line) == 0 {
 				continue
 			}
-			if len(line) == 0 {
-				continue
+
+			file := newFile
+			if file == "" {
+				file = oldFile
 			}
 
 			switch line[0] {
 			case '+':
-				// Only added lines are considered safe targets for inline comments.
-				if _, ok := result[currentFile]; !ok {
-					result[currentFile] = make(map[int]bool)
-				}
-				result[currentFile][newLine] = true
+				fn(diffLine{file: file, kind: '+', newLine: newLine, text: line[1:]})
 				newLine++
+				newLeft--
+			case '-':
+				fn(diffLine{file: file, kind: '-', oldLine: oldLine, text: line[1:]})
+				oldLine++
+				oldLeft--
 			case ' ':
+				fn(diffLine{file: file, kind: ' ', oldLine: oldLine, newLine: newLine, text: line[1:]})
+				oldLine++
 				newLine++
-			case '-':
-				// Removed lines belong only to the old side of the diff.
+				oldLeft--
+				newLeft--
+			default:
+				// E.g. "\ No newline at end of file".
+			}
+
+			if oldLeft <= 0 && newLeft <= 0 {
+				inHunk = false
 			}
 		}
 	}
+}
+
+// hunkLineCount parses the optional line count of a hunk header range, which defaults to 1.
+func hunkLineCount(s string) int {
+	if s == "" {
+		return 1
+	}
 
-	return result
+	return parseInt(s)
+}
+
+// diffFilePath converts a path from a ---/+++ line to a repository-relative path, "" for /dev/null.
+func diffFilePath(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "/dev/null" {
+		return ""
+	}
+
+	return normalizeDiffPath(path)
 }
 
 // normalizeDiffPath converts unified diff paths to repository-relative paths.
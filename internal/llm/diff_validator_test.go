@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const suggestionTestDiff = `diff --git a/pkg/a.go b/pkg/a.go
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -10,3 +10,5 @@ func A() {
+ 	x := 1
++	y := 2
++	z := y * 2
+ 	return x
++	w := 3
+`
+
+func TestValidateCommentsAgainstDiffKeepsSuggestionWithinAddedLines(t *testing.T) {
+	comments := validateCommentsAgainstDiff(suggestionTestDiff, []*ReviewComment{
+		{FilePath: "pkg/a.go", LineNumber: 11, Suggestion: &Suggestion{StartLine: 11, EndLine: 12, NewText: "\tz := 4"}},
+		{FilePath: "pkg/a.go", LineNumber: 14, Suggestion: &Suggestion{StartLine: 14, NewText: "\tw := 4"}},
+	})
+
+	require.NotNil(t, comments[0].Suggestion)
+	require.Equal(t, "\ty := 2\n\tz := y * 2", comments[0].Suggestion.OldText)
+	require.NotNil(t, comments[1].Suggestion)
+	require.Equal(t, 14, comments[1].Suggestion.EndLine)
+	require.Equal(t, "\tw := 3", comments[1].Suggestion.OldText)
+}
+
+func TestValidateCommentsAgainstDiffDropsSuggestionOutsideAddedLines(t *testing.T) {
+	comments := validateCommentsAgainstDiff(suggestionTestDiff, []*ReviewComment{
+		{FilePath: "pkg/a.go", LineNumber: 12, Suggestion: &Suggestion{StartLine: 12, EndLine: 13, NewText: "\treturn z"}},
+		{FilePath: "pkg/a.go", LineNumber: 11, Suggestion: &Suggestion{StartLine: 12, EndLine: 11}},
+		{FilePath: "pkg/b.go", LineNumber: 1, Suggestion: &Suggestion{StartLine: 1, EndLine: 1}},
+	})
+
+	for _, c := range comments {
+		require.Nil(t, c.Suggestion)
+	}
+}
+
+func TestValidateCommentsAgainstDiffChecksEndLine(t *testing.T) {
+	comments := validateCommentsAgainstDiff(suggestionTestDiff, []*ReviewComment{
+		{FilePath: "pkg/a.go", LineNumber: 11, EndLineNumber: 12},
+		{FilePath: "pkg/a.go", LineNumber: 12, EndLineNumber: 14},
+		{FilePath: "pkg/a.go", LineNumber: 13, EndLineNumber: 14},
+	})
+
+	require.True(t, comments[0].LineVerified)
+	require.Equal(t, 12, comments[0].EndLineNumber)
+
+	require.True(t, comments[1].LineVerified)
+	require.Equal(t, 12, comments[1].LineNumber)
+	require.Zero(t, comments[1].EndLineNumber)
+
+	require.False(t, comments[2].LineVerified)
+	require.Zero(t, comments[2].LineNumber)
+	require.Zero(t, comments[2].EndLineNumber)
+	require.Equal(t, 13, comments[2].ReportedLine)
+}
+
+func TestValidateCommentsAgainstDiffOldSide(t *testing.T) {
+	const diff = `--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -10,4 +10,2 @@ func A() {
+ 	x := load()
+-	if x == nil {
+-		return nil
+-	}
++	use(x)
+--- a/pkg/gone.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package gone
+-var Gone = 1
+`
+
+	comments := validateCommentsAgainstDiff(diff, []*ReviewComment{
+		{FilePath: "pkg/a.go", LineNumber: 11, EndLineNumber: 13, Side: SideOld, Suggestion: &Suggestion{StartLine: 11}},
+		{FilePath: "pkg/a.go", LineNumber: 11, Side: SideNew},
+		{FilePath: "pkg/gone.go", LineNumber: 2, Side: SideOld},
+		{FilePath: "pkg/a.go", LineNumber: 10, Side: SideOld},
+	})
+
+	require.True(t, comments[0].LineVerified)
+	require.Equal(t, 13, comments[0].EndLineNumber)
+	require.Nil(t, comments[0].Suggestion)
+
+	require.True(t, comments[1].LineVerified, "line 11 of the new file is added")
+
+	require.True(t, comments[2].LineVerified, "removed lines of deleted files are valid targets")
+
+	require.False(t, comments[3].LineVerified, "context lines are not valid targets")
+}
+
+func TestForEachAddedLineWithConcatenatedDiffs(t *testing.T) {
+	const diff = `--- a/a.go
++++ b/a.go
+@@ -1,1 +1,2 @@
+ a
++b
+--- a/b.go
++++ b/b.go
+@@ -5 +5 @@
+-c
++d
+`
+
+	var got []string
+	forEachAddedLine(diff, func(file string, line int, text string) {
+		got = append(got, fmt.Sprintf("%s:%d:%s", file, line, text))
+	})
+
+	require.Equal(t, []string{"a.go:2:b", "b.go:5:d"}, got)
+}
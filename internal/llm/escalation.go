@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"errors"
+
+	"github.com/groall/upsource-go-client/client"
+)
+
+const defaultEscalationSystemMessage = `You are an AI code reviewer. You and the author of the change could not agree in a code review discussion,
+and a human reviewer has been asked to decide.
+
+Summarise the discussion for the human reviewer, using the code context and the discussion so far:
+
+- the concern that was raised and the code it is about,
+- the position and the main arguments of each side,
+- what, if anything, both sides already agree on.
+
+Be neutral: do not take sides, do not repeat your arguments more strongly than the author's,
+and do not recommend an outcome. Keep the summary under 150 words.
+
+Reply ONLY with a JSON object:
+
+{
+  "comment": "<summary>",
+  "close": false
+}`
+
+// SummarizeDisagreement asks the LLM for a neutral summary of both positions in a discussion
+// that is handed over to a human reviewer. The code context is the anchored code, see anchoredCodeContext.
+func (rr *ReviewReplier) SummarizeDisagreement(d client.DiscussionInFileDTO, botUserID string) (string, error) {
+	ac, err := rr.anchoredCodeContext(d)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := rr.reply(d, botUserID, rr.replier.templates.escalation, ac.code, ac.anchor)
+	if err != nil {
+		return "", err
+	}
+	if result.Comment == "" {
+		return "", errors.New("LLM returned an empty disagreement summary")
+	}
+
+	return result.Comment, nil
+}
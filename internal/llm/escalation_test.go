@@ -0,0 +1,39 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+	"github.com/groall/upsource-go-client/client"
+)
+
+func TestSummarizeDisagreement(t *testing.T) {
+	discussion := client.DiscussionInFileDTO{
+		Anchor: client.AnchorDTO{FileID: "/b.go"},
+		Comments: []client.CommentDTO{
+			{AuthorID: "bot", Text: "The package name is misleading."},
+			{AuthorID: "dev", Text: "It matches the directory."},
+		},
+	}
+	provider := &recordingProvider{response: `{"comment":"The bot finds the name misleading; the author keeps it to match the directory.","close":true}`}
+	reviewer := &Reviewer{
+		llmProvider: provider,
+		gitProvider: &replierMockGitProvider{changes: mentionTestDiff},
+		ctx:         context.Background(),
+	}
+	rr := mustNewReplier(t, reviewer, ReplyConfig{Users: fakeUserNames{"dev": "Alice"}}, nil).ForReview(&upsource.Review{})
+
+	summary, err := rr.SummarizeDisagreement(discussion, "bot")
+	require.NoError(t, err)
+	require.Equal(t, "The bot finds the name misleading; the author keeps it to match the directory.", summary)
+	require.Equal(t, defaultEscalationSystemMessage, provider.systemPrompt)
+	require.Contains(t, provider.userPrompt, "+package bb")
+	require.Contains(t, provider.userPrompt, "Human (Alice):\nIt matches the directory.")
+
+	provider.response = `{"comment":"","close":false}`
+	_, err = rr.SummarizeDisagreement(discussion, "bot")
+	require.EqualError(t, err, "LLM returned an empty disagreement summary")
+}
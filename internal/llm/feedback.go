@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+)
+
+const defaultFeedbackSystemMessage = `You are analysing how developers responded to a code review comment written by an AI reviewer.
+
+Read the discussion and classify the developers' response to the first comment:
+* "accepted": they agreed with the comment, fixed the code or said they will,
+* "disagreed": they rejected the comment as wrong, unimportant or not applicable,
+* "ignored": their replies do not respond to the comment.
+
+Respond ONLY with a JSON object:
+
+{"outcome": "accepted"|"disagreed"|"ignored"}`
+
+const feedbackUserPromptTemplate = `### Discussion (oldest first)
+
+%s
+`
+
+// ClassifyFeedback asks the LLM how developers responded to the comment that started a bot discussion,
+// using the discussion transcript.
+func (rr *ReviewReplier) ClassifyFeedback(d client.DiscussionInFileDTO, botUserID string) (store.Outcome, error) {
+	systemPrompt, err := rr.systemPrompt(rr.replier.templates.feedback, botUserID)
+	if err != nil {
+		return store.OutcomePending, err
+	}
+
+	log.Print("Sending feedback classification prompt to LLM...")
+
+	thread := formatThread(rr.threadTranscript(d, botUserID))
+	response, err := rr.replier.complete(fmt.Sprintf(feedbackUserPromptTemplate, thread), systemPrompt)
+	if err != nil {
+		metrics.DefaultRecorder.RecordLLMError(metrics.OperationReply, rr.replier.cfg.ActiveProvider)
+		return store.OutcomePending, fmt.Errorf("LLM feedback request failed: %w", err)
+	}
+
+	var result struct {
+		Outcome store.Outcome `json:"outcome"`
+	}
+	if err := json.Unmarshal([]byte(parseLLMDiscissionReply(response)), &result); err != nil {
+		return store.OutcomePending, fmt.Errorf("failed to parse LLM feedback classification: %w", err)
+	}
+
+	switch result.Outcome {
+	case store.OutcomeAccepted, store.OutcomeDisagreed, store.OutcomeIgnored:
+		return result.Outcome, nil
+	default:
+		return store.OutcomePending, fmt.Errorf("unknown outcome %q", result.Outcome)
+	}
+}
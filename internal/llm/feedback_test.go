@@ -0,0 +1,31 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+	"github.com/groall/upsource-go-client/client"
+)
+
+func TestClassifyFeedback(t *testing.T) {
+	discussion := client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+		{AuthorID: "bot", Text: "Possible nil dereference."},
+		{AuthorID: "dev", Text: "Good catch, fixed."},
+	}}
+
+	provider := &recordingProvider{response: "```json\n{\"outcome\": \"accepted\"}\n```"}
+	rr := mustNewReplier(t, &Reviewer{llmProvider: provider}, ReplyConfig{}, nil).ForReview(&upsource.Review{})
+
+	outcome, err := rr.ClassifyFeedback(discussion, "bot")
+	require.NoError(t, err)
+	require.Equal(t, store.OutcomeAccepted, outcome)
+	require.Equal(t, defaultFeedbackSystemMessage, provider.systemPrompt)
+	require.Contains(t, provider.userPrompt, "Human (dev):\nGood catch, fixed.")
+
+	provider.response = `{"outcome": "maybe"}`
+	_, err = rr.ClassifyFeedback(discussion, "bot")
+	require.EqualError(t, err, `unknown outcome "maybe"`)
+}
@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/internal/analyzer"
+)
+
+// filterFindingsToDiff keeps only the findings that point at added lines of the diff,
+// using the same line index that validates LLM comments.
+func filterFindingsToDiff(diff string, findings []analyzer.Finding) []analyzer.Finding {
+	if diff == "" || len(findings) == 0 {
+		return nil
+	}
+
+	fileLines := buildNewFileLineIndex(diff)
+
+	var out []analyzer.Finding
+	for _, f := range findings {
+		if lines, ok := fileLines[normalizeDiffPath(f.FilePath)]; ok && lines[f.Line] {
+			out = append(out, f)
+		}
+	}
+
+	return out
+}
+
+// formatFindings renders findings as a prompt section the LLM can explain and prioritise.
+func formatFindings(findings []analyzer.Finding) string {
+	if len(findings) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n### Static analysis findings on changed lines:\n\n")
+	b.WriteString("Explain the findings that point at real problems and include them in your review; skip false positives.\n\n")
+	for _, f := range findings {
+		_, _ = fmt.Fprintf(&b, "- %s:%d [%s] (%s) %s\n", f.FilePath, f.Line, findingSource(f), f.Severity, f.Message)
+	}
+
+	return b.String()
+}
+
+// findingsToComments converts findings to review comments that are posted without going through the LLM.
+func findingsToComments(findings []analyzer.Finding) []*ReviewComment {
+	comments := make([]*ReviewComment, 0, len(findings))
+	for _, f := range findings {
+		comments = append(comments, &ReviewComment{
+			FilePath:     normalizeDiffPath(f.FilePath),
+			LineNumber:   f.Line,
+			LineVerified: true,
+			Comment:      fmt.Sprintf("`%s`: %s", findingSource(f), f.Message),
+			Severity:     f.Severity,
+			Confidence:   1,
+		})
+	}
+
+	return comments
+}
+
+func findingSource(f analyzer.Finding) string {
+	if f.Rule == "" {
+		return f.Tool
+	}
+
+	return f.Tool + "/" + f.Rule
+}
@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/internal/analyzer"
+)
+
+const findingsTestDiff = `--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -10,3 +10,4 @@ func A() {
+ 	x := 1
++	y := 2
+ 	return x
++	z := 3
+`
+
+func TestFilterFindingsToDiff(t *testing.T) {
+	findings := []analyzer.Finding{
+		{Tool: "vet", FilePath: "pkg/a.go", Line: 11, Message: "on added line"},
+		{Tool: "vet", FilePath: "pkg/a.go", Line: 12, Message: "on context line"},
+		{Tool: "vet", FilePath: "pkg/b.go", Line: 11, Message: "other file"},
+		{Tool: "vet", FilePath: "pkg/a.go", Line: 13, Message: "last added line"},
+	}
+
+	got := filterFindingsToDiff(findingsTestDiff, findings)
+	require.Len(t, got, 2)
+	require.Equal(t, "on added line", got[0].Message)
+	require.Equal(t, "last added line", got[1].Message)
+}
+
+func TestFindingsToComments(t *testing.T) {
+	comments := findingsToComments([]analyzer.Finding{
+		{Tool: "staticcheck", Rule: "SA4006", FilePath: "pkg/a.go", Line: 11, Message: "value never used", Severity: SeverityMedium},
+	})
+
+	require.Equal(t, []*ReviewComment{{
+		FilePath:     "pkg/a.go",
+		LineNumber:   11,
+		LineVerified: true,
+		Comment:      "`staticcheck/SA4006`: value never used",
+		Severity:     SeverityMedium,
+		Confidence:   1,
+	}}, comments)
+}
@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// fixNotFoundComment replaces a reply that closes a discussion as fixed when the commented code has not changed.
+const fixNotFoundComment = "I can't see a change to the commented lines in the latest revision of the review yet, so I'm keeping this discussion open. I'll check again once the fix is pushed."
+
+// fixCheck is what is known about changes to the commented lines since the discussion was started.
+type fixCheck struct {
+	known   bool // Whether the commented lines could be compared with the latest revision.
+	changed bool
+	file    *upsource.FileAnchor
+	before  string
+	after   string
+}
+
+// checkFix compares the commented lines with the latest revision of the file in the review.
+// Lines that cannot be found at the latest revision count as changed.
+func checkFix(fa *upsource.FileAnchor) fixCheck {
+	if fa == nil || fa.StartLine == 0 || fa.HeadRevisionID == "" {
+		return fixCheck{}
+	}
+
+	check := fixCheck{known: true, file: fa, before: lineRange(fa.Content, fa.StartLine, fa.EndLine)}
+	if fa.HeadRevisionID == fa.RevisionID {
+		return check
+	}
+
+	start, end, found := locateLines(fa.Content, fa.HeadContent, fa.StartLine, fa.EndLine)
+	if found {
+		check.after = lineRange(fa.HeadContent, start, end)
+	}
+	check.changed = !found || check.after != check.before
+
+	return check
+}
+
+// prompt renders the check as a prompt section with the instructions for closing the discussion.
+func (c fixCheck) prompt() string {
+	if !c.known {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n### Changes to the commented lines\n\n")
+	if !c.changed {
+		b.WriteString("The commented lines have not changed since your comment.\n")
+		_, _ = fmt.Fprintf(&b, "Do not close the discussion as fixed. If the developer says it is fixed, say that the latest revision does not contain the fix yet. "+
+			"Close it only if you no longer consider it an issue, with \"resolution\": %q.\n", ResolutionWithdrawn)
+		return b.String()
+	}
+
+	b.WriteString(c.changes())
+	_, _ = fmt.Fprintf(&b, "Close the discussion with \"resolution\": %q only if the updated code demonstrably addresses your concern, "+
+		"or with \"resolution\": %q if you no longer consider it an issue. Otherwise keep it open and say what is still missing.\n",
+		ResolutionFixed, ResolutionWithdrawn)
+
+	return b.String()
+}
+
+// changes shows the commented lines before and after they changed.
+func (c fixCheck) changes() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "Before (revision %s):\n```\n%s\n```\n\n", c.file.RevisionID, c.before)
+	if c.after == "" {
+		_, _ = fmt.Fprintf(&b, "After (revision %s): the lines were rewritten or removed, see the code context above.\n\n", c.file.HeadRevisionID)
+	} else {
+		_, _ = fmt.Fprintf(&b, "After (revision %s):\n```\n%s\n```\n\n", c.file.HeadRevisionID, c.after)
+	}
+
+	return b.String()
+}
+
+// apply keeps the discussion open when the reply closes it as fixed although the commented lines have not changed.
+func (c fixCheck) apply(result *ReplyResult) {
+	if !c.known || c.changed || !result.Close || result.Resolution == ResolutionWithdrawn {
+		return
+	}
+
+	log.Printf("Keeping discussion on %s open: the commented lines have not changed since the comment\n", c.file.File)
+	result.Close = false
+	result.Comment = fixNotFoundComment
+}
+
+// lineRange returns the lines start..end of text.
+func lineRange(text string, start, end int) string {
+	lines := strings.Split(text, "\n")
+	if start < 1 || start > len(lines) {
+		return ""
+	}
+
+	return strings.Join(lines[start-1:min(max(end, start), len(lines))], "\n")
+}
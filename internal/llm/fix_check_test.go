@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+func TestCheckFix(t *testing.T) {
+	anchor := func(head string) *upsource.FileAnchor {
+		return &upsource.FileAnchor{
+			File: "a.go", RevisionID: "r1", StartLine: 2, EndLine: 3, Content: "package a\nx := 1\ny := x\n",
+			HeadRevisionID: "r2", HeadContent: head,
+		}
+	}
+
+	t.Run("unknown anchor", func(t *testing.T) {
+		check := checkFix(nil)
+		require.False(t, check.known)
+		require.Empty(t, check.prompt())
+
+		result := &ReplyResult{Comment: "Fixed.", Close: true, Resolution: ResolutionFixed}
+		check.apply(result)
+		require.True(t, result.Close)
+	})
+
+	t.Run("moved lines are unchanged", func(t *testing.T) {
+		check := checkFix(anchor("package a\n\nx := 1\ny := x\n"))
+		require.True(t, check.known)
+		require.False(t, check.changed)
+		require.Contains(t, check.prompt(), "have not changed since your comment")
+
+		result := &ReplyResult{Comment: "Thanks, fixed.", Close: true, Resolution: ResolutionFixed}
+		check.apply(result)
+		require.False(t, result.Close)
+		require.Equal(t, fixNotFoundComment, result.Comment)
+
+		withdrawn := &ReplyResult{Comment: "Fair point.", Close: true, Resolution: ResolutionWithdrawn}
+		check.apply(withdrawn)
+		require.True(t, withdrawn.Close)
+	})
+
+	t.Run("changed lines", func(t *testing.T) {
+		check := checkFix(anchor("package a\nx := 1\ny := x + 1\n"))
+		require.True(t, check.changed)
+		require.Equal(t, "x := 1\ny := x", check.before)
+		require.Equal(t, "x := 1\ny := x + 1", check.after)
+
+		prompt := check.prompt()
+		require.Contains(t, prompt, "Before (revision r1):\n```\nx := 1\ny := x\n```")
+		require.Contains(t, prompt, "After (revision r2):\n```\nx := 1\ny := x + 1\n```")
+
+		result := &ReplyResult{Comment: "Fixed.", Close: true, Resolution: ResolutionFixed}
+		check.apply(result)
+		require.True(t, result.Close)
+	})
+
+	t.Run("removed lines", func(t *testing.T) {
+		check := checkFix(anchor("package a\n"))
+		require.True(t, check.changed)
+		require.Empty(t, check.after)
+		require.Contains(t, check.prompt(), "the lines were rewritten or removed")
+	})
+}
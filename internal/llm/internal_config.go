@@ -1,13 +1,34 @@
 package llm
 
+import "github.com/groall/upsource-ai-reviewer/pkg/config"
+
 type ReviewConfig struct {
-	UserPromptTemplate string
-	SystemMessage      string
-	MaxPerReview       int
-	ActiveProvider     string
+	UserPromptTemplate     string
+	SystemMessage          string
+	MaxPerReview           int
+	ActiveProvider         string
+	AnalyzerMode           string
+	MaxFindings            int
+	Redaction              config.Redaction
+	Critique               config.Critique
+	Summary                config.Summary
+	PromptFragments        []config.PromptFragment
+	Tracker                config.Tracker
+	Suppressions           SuppressionSource
+	MaxSuppressionExamples int
+	Style                  config.Style
+	Users                  UserNames
 }
 
 type ReplyConfig struct {
-	SystemMessage  string
-	ActiveProvider string
+	SystemMessage           string
+	MentionSystemMessage    string
+	RecheckSystemMessage    string
+	FeedbackSystemMessage   string
+	EscalationSystemMessage string
+	ActiveProvider          string
+	Style                   config.Style
+	Memory                  config.Memory
+	Threads                 ThreadMemory
+	Users                   UserNames
 }
@@ -25,7 +25,16 @@ type PrefixCacheProvider interface {
 
 // createLLMProvider creates an LLM provider based on the configuration.
 func createLLMProvider(ctx context.Context, providers config.Providers) (Provider, error) {
-	switch providers.ActiveLLMProvider() {
+	return createNamedLLMProvider(ctx, providers, providers.ActiveLLMProvider(), "")
+}
+
+// createNamedLLMProvider creates the named LLM provider. A non-empty model overrides the configured one.
+func createNamedLLMProvider(ctx context.Context, providers config.Providers, name, model string) (Provider, error) {
+	if model != "" {
+		providers = withModel(providers, name, model)
+	}
+
+	switch name {
 	case config.ProviderAgent:
 		provider, err := pkgllm.NewAgentCompletion(ctx, &pkgllm.AgentConfig{
 			Command:        providers.Agent.Command,
@@ -75,3 +84,17 @@ func createLLMProvider(ctx context.Context, providers config.Providers) (Provide
 		return nil, fmt.Errorf("no LLM provider configured")
 	}
 }
+
+// withModel returns a copy of providers with the model of the named provider replaced.
+func withModel(providers config.Providers, name, model string) config.Providers {
+	switch name {
+	case config.ProviderOpenAI:
+		providers.OpenAI.Model = model
+	case config.ProviderGemini:
+		providers.Gemini.Model = model
+	case config.ProviderAnthropic:
+		providers.Anthropic.Model = model
+	}
+
+	return providers
+}
@@ -0,0 +1,67 @@
+package llm
+
+import (
+	"strings"
+
+	"github.com/groall/upsource-go-client/client"
+)
+
+const defaultMentionSystemMessage = `You are an AI code reviewer. A developer mentioned you in a code review discussion they started.
+
+Answer the latest message that mentions you, using the code context and the discussion so far.
+
+Reply ONLY with a JSON object:
+
+{
+  "comment": "<answer>",
+  "close": false
+}
+
+Rules:
+
+- Write ` + "`comment`" + ` in the same language as the message that mentions you.
+- Keep answers concise and specific to the code being discussed.
+- If you are not sure, say so instead of guessing.
+- If the message does not ask anything of you, return an empty ` + "`comment`" + `.`
+
+// AnswerMention asks the LLM to answer a comment mentioning the bot in a discussion started by a human.
+// The code context is the anchored code, see anchoredCodeContext.
+// The bot never closes such discussions, so Close is always false.
+func (rr *ReviewReplier) AnswerMention(d client.DiscussionInFileDTO, botUserID string) (*ReplyResult, error) {
+	ac, err := rr.anchoredCodeContext(d)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := rr.reply(d, botUserID, rr.replier.templates.mention, ac.code, ac.anchor)
+	if err != nil {
+		return nil, err
+	}
+	result.Close = false
+
+	return result, nil
+}
+
+// fileDiff returns the part of a unified diff that changes path, or "" when it is not changed.
+func fileDiff(diff, path string) string {
+	if path == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	var inFile bool
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			oldPath := diffFilePath(strings.TrimPrefix(line, "--- "))
+			newPath := diffFilePath(strings.TrimPrefix(lines[i+1], "+++ "))
+			inFile = oldPath == path || newPath == path
+		}
+		if inFile {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
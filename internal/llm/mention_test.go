@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+	"github.com/groall/upsource-go-client/client"
+)
+
+const mentionTestDiff = `--- a/a.go
++++ b/a.go
+@@ -1,1 +1,1 @@
+-package a
++package aa
+--- a/b.go
++++ b/b.go
+@@ -1,1 +1,1 @@
+-package b
++package bb
+`
+
+func TestFileDiff(t *testing.T) {
+	require.Equal(t, "--- a/b.go\n+++ b/b.go\n@@ -1,1 +1,1 @@\n-package b\n+package bb", fileDiff(mentionTestDiff, "b.go"))
+	require.Empty(t, fileDiff(mentionTestDiff, "c.go"))
+	require.Empty(t, fileDiff(mentionTestDiff, ""))
+}
+
+type recordingProvider struct {
+	response     string
+	userPrompt   string
+	systemPrompt string
+}
+
+func (p *recordingProvider) Completion(userPrompt, systemPrompt string) (string, error) {
+	p.userPrompt, p.systemPrompt = userPrompt, systemPrompt
+	return p.response, nil
+}
+
+func TestAnswerMention(t *testing.T) {
+	provider := &recordingProvider{response: `{"comment":"It is safe.","close":true}`}
+	reviewer := &Reviewer{
+		llmProvider: provider,
+		gitProvider: &replierMockGitProvider{changes: mentionTestDiff},
+		ctx:         context.Background(),
+	}
+
+	result, err := mustNewReplier(t, reviewer, ReplyConfig{}, nil).ForReview(&upsource.Review{}).AnswerMention(
+		client.DiscussionInFileDTO{
+			Anchor:   client.AnchorDTO{FileID: "/b.go"},
+			Comments: []client.CommentDTO{{AuthorID: "dev", Text: "@bot is this safe?"}},
+		},
+		"bot",
+	)
+	require.NoError(t, err)
+	require.Equal(t, &ReplyResult{Comment: "It is safe."}, result)
+	require.Equal(t, defaultMentionSystemMessage, provider.systemPrompt)
+	require.Contains(t, provider.userPrompt, "+package bb")
+	require.NotContains(t, provider.userPrompt, "+package aa")
+}
@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// newPromptData collects the data available to prompt templates for a review.
+// The author and the reviewers are shown by name when users can resolve them. The bot is not listed as a reviewer.
+func newPromptData(review *upsource.Review, diff, messages string, maxPerReview int, botUserID string, users UserNames) prompt.Data {
+	files := diffFileStats(diff)
+
+	author := review.GetAuthorID()
+	var reviewers []string
+	for _, id := range review.GetReviewerIDs() {
+		if id != botUserID {
+			reviewers = append(reviewers, id)
+		}
+	}
+	if users != nil {
+		names := users.UserNames(append([]string{author}, reviewers...))
+		author = displayName(names, author)
+		for i, id := range reviewers {
+			reviewers[i] = displayName(names, id)
+		}
+	}
+
+	return prompt.Data{
+		Title:        review.GetTitle(),
+		Description:  review.GetDescription(),
+		Branch:       review.GetBranch(),
+		Author:       author,
+		Reviewers:    reviewers,
+		Issues:       reviewIssues(review),
+		Project:      review.GetProjectID(),
+		Labels:       review.GetLabels(),
+		Files:        files,
+		Stats:        prompt.StatsOf(files),
+		Diff:         diff,
+		Messages:     messages,
+		MaxPerReview: maxPerReview,
+		Language:     prompt.GuessLanguage(files),
+	}
+}
+
+// displayName returns the name of a user, or its ID when the name is unknown.
+func displayName(names map[string]string, userID string) string {
+	if name := names[userID]; name != "" {
+		return name
+	}
+
+	return userID
+}
+
+// diffFileStats counts added and removed lines per file of a unified diff, in the order the files appear.
+func diffFileStats(diff string) []prompt.File {
+	var files []prompt.File
+	index := make(map[string]int)
+
+	walkDiff(diff, func(l diffLine) {
+		i, ok := index[l.file]
+		if !ok {
+			i = len(files)
+			index[l.file] = i
+			files = append(files, prompt.File{Path: l.file})
+		}
+
+		switch l.kind {
+		case '+':
+			files[i].Added++
+		case '-':
+			files[i].Removed++
+		}
+	})
+
+	return files
+}
+
+// reviewIssues returns the issues linked to the review followed by the issue keys mentioned in its title and branch.
+func reviewIssues(review *upsource.Review) []string {
+	return prompt.IssueKeys(append(review.GetIssueIDs(), review.GetTitle(), review.GetBranch())...)
+}
+
+// reviewContext renders the review metadata as a prompt section, for templates that do not reference it.
+func reviewContext(data prompt.Data) string {
+	if data.Title == "" && data.Description == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("### Review:\n\n")
+	_, _ = fmt.Fprintf(&b, "Title: %s\n", data.Title)
+	if data.Branch != "" {
+		_, _ = fmt.Fprintf(&b, "Branch: %s\n", data.Branch)
+	}
+	if data.Author != "" {
+		_, _ = fmt.Fprintf(&b, "Author: %s\n", data.Author)
+	}
+	if len(data.Reviewers) > 0 {
+		_, _ = fmt.Fprintf(&b, "Reviewers: %s\n", strings.Join(data.Reviewers, ", "))
+	}
+	if len(data.Issues) > 0 {
+		_, _ = fmt.Fprintf(&b, "Issues: %s\n", strings.Join(data.Issues, ", "))
+	}
+	if data.Description != "" {
+		_, _ = fmt.Fprintf(&b, "\nDescription:\n%s\n", strings.TrimSpace(data.Description))
+	}
+	b.WriteString("\nCheck that the change does what the review states and point out anything that contradicts it.\n\n")
+
+	return b.String()
+}
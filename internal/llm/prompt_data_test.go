@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+	"github.com/groall/upsource-go-client/client"
+)
+
+func TestNewPromptData(t *testing.T) {
+	const diff = `--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@
+ package main
+-func a() {}
++func b() {}
++func c() {}
+--- a/old.go
++++ /dev/null
+@@ -1,1 +0,0 @@
+-package old
+`
+	data := newPromptData(&upsource.Review{}, diff, "fix", 5, "", nil)
+
+	require.Equal(t, []prompt.File{
+		{Path: "main.go", Added: 2, Removed: 1},
+		{Path: "old.go", Removed: 1},
+	}, data.Files)
+	require.Equal(t, prompt.Stats{Files: 2, Added: 2, Removed: 2}, data.Stats)
+	require.Equal(t, "Go", data.Language)
+	require.Equal(t, "fix", data.Messages)
+	require.Equal(t, 5, data.MaxPerReview)
+}
+
+func TestNewPromptDataResolvesUserNames(t *testing.T) {
+	review := upsource.NewReview(&client.ReviewDescriptorDTO{
+		CreatedBy: "u1",
+		Participants: []client.ParticipantInReviewDTO{
+			{UserID: "u2", Role: client.Reviewer},
+			{UserID: "bot", Role: client.Reviewer},
+			{UserID: "u3", Role: client.Reviewer},
+		},
+	})
+
+	data := newPromptData(review, "", "", 0, "bot", fakeUserNames{"u1": "Alice", "u2": "Bob", "bot": "AI Reviewer"})
+	require.Equal(t, "Alice", data.Author)
+	require.Equal(t, []string{"Bob", "u3"}, data.Reviewers)
+
+	data = newPromptData(review, "", "", 0, "bot", nil)
+	require.Equal(t, "u1", data.Author)
+	require.Equal(t, []string{"u2", "u3"}, data.Reviewers)
+}
+
+func TestReviewContext(t *testing.T) {
+	require.Empty(t, reviewContext(prompt.Data{Branch: "master"}))
+
+	require.Equal(t, "### Review:\n\n"+
+		"Title: PROJ-1: retry uploads\n"+
+		"Branch: feature/PROJ-1\n"+
+		"Issues: PROJ-1\n"+
+		"\nDescription:\nUploads fail on flaky networks.\n"+
+		"\nCheck that the change does what the review states and point out anything that contradicts it.\n\n",
+		reviewContext(prompt.Data{
+			Title:       "PROJ-1: retry uploads",
+			Description: "Uploads fail on flaky networks.\n",
+			Branch:      "feature/PROJ-1",
+			Issues:      []string{"PROJ-1"},
+		}))
+}
@@ -0,0 +1,39 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+// diffFiles returns the files with hunks in a unified diff, in the order they appear.
+func diffFiles(diff string) []string {
+	stats := diffFileStats(diff)
+
+	files := make([]string, 0, len(stats))
+	for _, f := range stats {
+		files = append(files, f.Path)
+	}
+
+	return files
+}
+
+// promptFragmentsFor renders the prompt fragments matching at least one of the files as a system prompt section.
+// It works on a list of files, so a part of a diff gets only the fragments for the files it contains.
+func promptFragmentsFor(files []string, fragments []config.PromptFragment) string {
+	var b strings.Builder
+	for i := range fragments {
+		fragment := &fragments[i]
+		for _, file := range files {
+			if fragment.Matches(file) {
+				log.Printf("Adding prompt fragment %s for %s\n", fragment.Name, file)
+				_, _ = fmt.Fprintf(&b, "\n\n## Additional guidelines: %s\n\n%s", fragment.Name, strings.TrimSpace(fragment.Text))
+				break
+			}
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+func TestPromptFragmentsFor(t *testing.T) {
+	const diff = `--- a/db/001.sql
++++ b/db/001.sql
+@@ -1,0 +1,1 @@
++ALTER TABLE users ADD COLUMN age int;
+--- a/main.go
++++ b/main.go
+@@ -1,1 +1,1 @@
+-package main
++package app
+`
+	fragments := []config.PromptFragment{
+		{Name: "SQL migrations", Globs: []string{"*.sql"}, Text: "Check for table locks.\n"},
+		{Name: "Terraform", Globs: []string{"*.tf"}, Text: "Check for resource replacement."},
+		{Name: "Go", Globs: []string{"*.go"}, Text: "Check error handling."},
+	}
+
+	files := diffFiles(diff)
+	require.Equal(t, []string{"db/001.sql", "main.go"}, files)
+
+	require.Equal(t,
+		"\n\n## Additional guidelines: SQL migrations\n\nCheck for table locks."+
+			"\n\n## Additional guidelines: Go\n\nCheck error handling.",
+		promptFragmentsFor(files, fragments))
+
+	require.Empty(t, promptFragmentsFor([]string{"README.md"}, fragments))
+}
@@ -0,0 +1,73 @@
+package llm
+
+import (
+	"log"
+
+	"github.com/groall/upsource-go-client/client"
+)
+
+const defaultRecheckSystemMessage = `You are an AI code reviewer re-checking one of your earlier review comments.
+Nobody has answered it, but the code it is about has changed since.
+
+Decide whether the issue you raised still applies to the latest revision, using the commented lines
+before and after the change and the code context.
+
+Reply ONLY with a JSON object:
+
+{
+  "comment": "<short note>",
+  "close": true|false
+}
+
+Rules:
+
+- Set ` + "`close`" + ` to true only if the issue no longer applies, for example because the code was removed
+  or rewritten without the problem. ` + "`comment`" + ` then says in one sentence why, in the language of your earlier comment.
+- If the issue still applies, even in a different form, or you are not sure, set ` + "`close`" + ` to false
+  and leave ` + "`comment`" + ` empty.`
+
+// staleDiscussionComment is posted when the LLM resolves a stale discussion without a note.
+const staleDiscussionComment = "The commented code has changed and this issue no longer applies. Resolving."
+
+// Recheck asks the LLM whether the issue of a bot discussion still applies after its anchored lines changed.
+// It returns nil without asking when the anchor cannot be resolved, the lines have not changed,
+// or the discussion was already rechecked at the current head revision of the file.
+// A result that does not close the discussion has an empty comment.
+func (rr *ReviewReplier) Recheck(d client.DiscussionInFileDTO, botUserID string) (*ReplyResult, error) {
+	if rr.replier.anchors == nil || d.Anchor.FileID == "" {
+		return nil, nil
+	}
+
+	fa, err := rr.replier.anchors.ResolveFileAnchor(rr.review, d.Anchor)
+	if err != nil {
+		log.Printf("Skipping recheck of discussion %s: failed to resolve anchor: %v\n", d.DiscussionID, err)
+		return nil, nil
+	}
+
+	check := checkFix(fa)
+	if !check.known || !check.changed || rr.replier.rechecked[d.DiscussionID] == fa.HeadRevisionID {
+		return nil, nil
+	}
+
+	diff, err := rr.loadCodeContext()
+	if err != nil {
+		return nil, err
+	}
+
+	codeContext := formatFileAnchor(fa, fileDiff(diff, fa.File)) + "\n### Changes to the commented lines\n\n" + check.changes()
+	result, err := rr.reply(d, botUserID, rr.replier.templates.recheck, codeContext, describeFileAnchor(fa))
+	if err != nil {
+		return nil, err
+	}
+	rr.replier.rechecked[d.DiscussionID] = fa.HeadRevisionID
+
+	if !result.Close {
+		result.Comment = ""
+		return result, nil
+	}
+	if result.Comment == "" {
+		result.Comment = staleDiscussionComment
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+	"github.com/groall/upsource-go-client/client"
+)
+
+func TestRecheck(t *testing.T) {
+	discussion := client.DiscussionInFileDTO{
+		DiscussionID: "d1",
+		Anchor:       client.AnchorDTO{FileID: "/b.go", RevisionID: "r1"},
+		Comments:     []client.CommentDTO{{AuthorID: "bot", Text: "Rename the package."}},
+	}
+	newReplier := func(provider Provider, head string) *Replier {
+		reviewer := &Reviewer{llmProvider: provider, gitProvider: &replierMockGitProvider{changes: mentionTestDiff}, ctx: context.Background()}
+		return mustNewReplier(t, reviewer, ReplyConfig{}, &fakeAnchorResolver{anchor: &upsource.FileAnchor{
+			File: "b.go", RevisionID: "r1", StartLine: 1, EndLine: 1, Content: "package b",
+			HeadRevisionID: "r2", HeadContent: head,
+		}})
+	}
+
+	t.Run("unchanged lines are not sent to the LLM", func(t *testing.T) {
+		provider := &recordingProvider{response: `{"comment":"","close":true}`}
+
+		result, err := newReplier(provider, "\npackage b").ForReview(&upsource.Review{}).Recheck(discussion, "bot")
+		require.NoError(t, err)
+		require.Nil(t, result)
+		require.Empty(t, provider.userPrompt)
+	})
+
+	t.Run("changed lines", func(t *testing.T) {
+		provider := &recordingProvider{response: `{"comment":"","close":true}`}
+		replier := newReplier(provider, "package bb")
+
+		result, err := replier.ForReview(&upsource.Review{}).Recheck(discussion, "bot")
+		require.NoError(t, err)
+		require.Equal(t, &ReplyResult{Comment: staleDiscussionComment, Close: true}, result)
+		require.Equal(t, defaultRecheckSystemMessage, provider.systemPrompt)
+		require.Contains(t, provider.userPrompt, "Before (revision r1):\n```\npackage b\n```")
+		require.Contains(t, provider.userPrompt, "Rename the package.")
+
+		// The same head revision is not checked twice.
+		provider.userPrompt = ""
+		result, err = replier.ForReview(&upsource.Review{}).Recheck(discussion, "bot")
+		require.NoError(t, err)
+		require.Nil(t, result)
+		require.Empty(t, provider.userPrompt)
+	})
+
+	t.Run("issue still applies", func(t *testing.T) {
+		provider := &recordingProvider{response: `{"comment":"Still wrong.","close":false}`}
+
+		result, err := newReplier(provider, "package bb").ForReview(&upsource.Review{}).Recheck(discussion, "bot")
+		require.NoError(t, err)
+		require.Equal(t, &ReplyResult{}, result)
+	})
+}
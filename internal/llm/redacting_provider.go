@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/internal/redact"
+)
+
+// redactingProvider masks secrets and personal data in prompts before they reach the wrapped provider.
+// System prompts are masked too: their templates may render the diff, the review description or code context.
+type redactingProvider struct {
+	provider Provider
+	redactor *redact.Redactor
+}
+
+func newRedactingProvider(provider Provider, redactor *redact.Redactor) *redactingProvider {
+	return &redactingProvider{
+		provider: provider,
+		redactor: redactor,
+	}
+}
+
+func (p *redactingProvider) Completion(userPrompt, systemPrompt string) (string, error) {
+	return p.provider.Completion(p.redact(userPrompt), p.redact(systemPrompt))
+}
+
+// CompletionWithPrefixCache keeps prefix caching available when the wrapped provider supports it.
+func (p *redactingProvider) CompletionWithPrefixCache(userPromptPrefix, userPromptSuffix, systemPrompt string) (string, error) {
+	prefix := p.redact(userPromptPrefix)
+	suffix := p.redact(userPromptSuffix)
+	systemPrompt = p.redact(systemPrompt)
+
+	if cached, ok := p.provider.(PrefixCacheProvider); ok {
+		return cached.CompletionWithPrefixCache(prefix, suffix, systemPrompt)
+	}
+
+	return p.provider.Completion(prefix+suffix, systemPrompt)
+}
+
+func (p *redactingProvider) redact(text string) string {
+	redacted, matches := p.redactor.Redact(text)
+	if len(matches) > 0 {
+		log.Printf("Redacted %s before sending the prompt to LLM.\n", summarizeRedactions(matches))
+	}
+
+	for _, m := range matches {
+		metrics.DefaultRecorder.RecordRedaction(m.Detector)
+	}
+
+	return redacted
+}
+
+// summarizeRedactions describes matches by detector, e.g. "3 values (email x2, jwt x1)".
+func summarizeRedactions(matches []redact.Match) string {
+	counts := make(map[string]int)
+	for _, m := range matches {
+		counts[m.Detector]++
+	}
+
+	detectors := make([]string, 0, len(counts))
+	for detector := range counts {
+		detectors = append(detectors, detector)
+	}
+	sort.Strings(detectors)
+
+	parts := make([]string, 0, len(detectors))
+	for _, detector := range detectors {
+		parts = append(parts, fmt.Sprintf("%s x%d", detector, counts[detector]))
+	}
+
+	return fmt.Sprintf("%d values (%s)", len(matches), strings.Join(parts, ", "))
+}
+
+// secretComments returns a high severity comment for every added line of the diff that contains a secret.
+func secretComments(diff string, redactor *redact.Redactor) []*ReviewComment {
+	var comments []*ReviewComment
+
+	forEachAddedLine(diff, func(file string, line int, text string) {
+		_, matches := redactor.Redact(text)
+		for _, m := range matches {
+			if !m.Secret {
+				continue
+			}
+			comments = append(comments, &ReviewComment{
+				FilePath:     file,
+				LineNumber:   line,
+				LineVerified: true,
+				Comment:      fmt.Sprintf("Possible secret committed (`%s`). Remove it from the change and rotate the credential: it stays in the repository history even after the line is deleted.", m.Detector),
+				Severity:     SeverityHigh,
+				Confidence:   1,
+			})
+			return // One comment per line is enough.
+		}
+	})
+
+	return comments
+}
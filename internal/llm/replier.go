@@ -1,6 +1,7 @@
 package llm
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/groall/upsource-ai-reviewer/internal/git"
 	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
 	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
 	"github.com/groall/upsource-go-client/client"
 )
@@ -16,22 +18,74 @@ import (
 type Replier struct {
 	llmProvider Provider
 	gitProvider git.Provider
+	anchors     AnchorResolver
 	cfg         ReplyConfig
+	templates   replyTemplates
+
+	// rechecked maps discussion IDs to the head revision their anchored lines were last rechecked at.
+	rechecked map[string]string
 }
 
 type ReviewReplier struct {
 	replier     *Replier
 	review      *upsource.Review
 	codeContext string
+	commits     string // Commit messages of the review, loaded with the code context.
 	loaded      bool
 }
 
-func NewReplier(reviewer *Reviewer, cfg ReplyConfig) *Replier {
-	return &Replier{
+// replyTemplates are the parsed system messages of the reply passes.
+// reply is nil when replies.systemMessage is not configured.
+type replyTemplates struct {
+	reply, mention, recheck, feedback, escalation, threadSummary, explain, fix *replyTemplate
+}
+
+// replyTemplate is a parsed reply system message.
+type replyTemplate struct {
+	*prompt.Template
+	// usesDiff is set when the template references the review diff or commit messages, which are then loaded for it.
+	usesDiff bool
+}
+
+// NewReplier creates a Replier sharing the reviewer's providers. Without anchors,
+// replies in file discussions get the diff of the file as code context.
+func NewReplier(reviewer *Reviewer, cfg ReplyConfig, anchors AnchorResolver) (*Replier, error) {
+	r := &Replier{
 		llmProvider: reviewer.llmProvider,
 		gitProvider: reviewer.gitProvider,
+		anchors:     anchors,
 		cfg:         cfg,
+		rechecked:   make(map[string]string),
+	}
+
+	templates := []struct {
+		name, text string
+		tmpl       **replyTemplate
+	}{
+		{"replies.systemMessage", cfg.SystemMessage, &r.templates.reply},
+		{"replies.mentions.systemMessage", cmp.Or(cfg.MentionSystemMessage, defaultMentionSystemMessage), &r.templates.mention},
+		{"replies.sweep.systemMessage", cmp.Or(cfg.RecheckSystemMessage, defaultRecheckSystemMessage), &r.templates.recheck},
+		{"feedback.systemMessage", cmp.Or(cfg.FeedbackSystemMessage, defaultFeedbackSystemMessage), &r.templates.feedback},
+		{"replies.escalation.systemMessage", cmp.Or(cfg.EscalationSystemMessage, defaultEscalationSystemMessage), &r.templates.escalation},
+		{"replies.memory.systemMessage", cmp.Or(cfg.Memory.SystemMessage, defaultThreadSummarySystemMessage), &r.templates.threadSummary},
+		{"explain", explainSystemMessage, &r.templates.explain},
+		{"fix", fixSystemMessage, &r.templates.fix},
+	}
+	for _, t := range templates {
+		if t.text == "" {
+			continue
+		}
+		tmpl, err := prompt.Parse(t.name, t.text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", t.name, err)
+		}
+		*t.tmpl = &replyTemplate{
+			Template: tmpl,
+			usesDiff: prompt.Uses(t.text, "Diff") || prompt.Uses(t.text, "Messages"),
+		}
 	}
+
+	return r, nil
 }
 
 func (r *Replier) ForReview(review *upsource.Review) *ReviewReplier {
@@ -51,15 +105,33 @@ type CommentMsg struct {
 type ReplyResult struct {
 	Comment string `json:"comment"`
 	Close   bool   `json:"close"`
+	// Resolution tells why a discussion is closed: ResolutionFixed or ResolutionWithdrawn.
+	Resolution string `json:"resolution,omitempty"`
+	// Escalate asks for a human reviewer to decide, when the bot and the author keep disagreeing.
+	Escalate bool `json:"escalate,omitempty"`
 }
 
-const replyUserPromptPrefixTemplate = `### Original code context
+const (
+	// ResolutionFixed closes a discussion because the code was changed to address it.
+	ResolutionFixed = "fixed"
+	// ResolutionWithdrawn closes a discussion because the bot no longer considers it an issue.
+	ResolutionWithdrawn = "withdrawn"
+)
+
+const replyUserPromptPrefixTemplate = `### Code context
 %s
 
 ### Discussion anchor
 %s
 
-### Discussion so far (oldest first)
+`
+
+const replyThreadHeader = "### Discussion so far (oldest first)\n"
+
+const replySummarizedThreadTemplate = `### Summary of the earlier discussion
+%s
+
+### Latest messages of the discussion (oldest first)
 `
 
 // Reply asks the LLM to produce a follow-up reply for a discussion thread.
@@ -68,20 +140,36 @@ func (r *Replier) Reply(review *upsource.Review, d client.DiscussionInFileDTO, b
 }
 
 func (rr *ReviewReplier) Reply(d client.DiscussionInFileDTO, botUserID string) (*ReplyResult, error) {
-	if rr.replier.cfg.SystemMessage == "" {
+	if rr.replier.templates.reply == nil {
 		return nil, fmt.Errorf("replies.systemMessage is not configured")
 	}
 
-	thread := buildThreadTranscript(d.Comments, botUserID)
+	ac, err := rr.anchoredCodeContext(d)
+	if err != nil {
+		return nil, err
+	}
+
+	check := checkFix(ac.file)
+	result, err := rr.reply(d, botUserID, rr.replier.templates.reply, ac.code+check.prompt(), ac.anchor)
+	if err != nil {
+		return nil, err
+	}
+	check.apply(result)
+
+	return result, nil
+}
 
-	codeContext, err := rr.loadCodeContext()
+// reply renders the system message, sends the discussion with its code context to the LLM and parses its reply.
+func (rr *ReviewReplier) reply(d client.DiscussionInFileDTO, botUserID string, systemMessage *replyTemplate, codeContext, anchorText string) (*ReplyResult, error) {
+	summary, threadText := rr.threadPrompt(d, botUserID)
+
+	systemPrompt, err := rr.systemPrompt(systemMessage, botUserID)
 	if err != nil {
 		return nil, err
 	}
-	anchorText := buildReplyAnchorText(d.Anchor)
+	systemPrompt += outputStyle(rr.replier.cfg.Style, rr.review, rr.commits)
 
-	threadText := formatThread(thread)
-	userPrompt, prefix, suffix := buildReplyPromptParts(codeContext, anchorText, threadText)
+	userPrompt, prefix, suffix := buildReplyPromptParts(codeContext, anchorText, summary, threadText)
 
 	log.Print("Sending reply prompt to LLM...")
 
@@ -89,16 +177,16 @@ func (rr *ReviewReplier) Reply(d client.DiscussionInFileDTO, botUserID string) (
 	var llmErr error
 	if prefix != "" {
 		if p, ok := rr.replier.llmProvider.(PrefixCacheProvider); ok {
-			replyText, llmErr = p.CompletionWithPrefixCache(prefix, suffix, rr.replier.cfg.SystemMessage)
+			replyText, llmErr = p.CompletionWithPrefixCache(prefix, suffix, systemPrompt)
 			if llmErr != nil {
 				log.Printf("Prefix-cache reply failed, retrying without prefix cache: %v", llmErr)
-				replyText, llmErr = rr.replier.complete(userPrompt, rr.replier.cfg.SystemMessage)
+				replyText, llmErr = rr.replier.complete(userPrompt, systemPrompt)
 			}
 		} else {
-			replyText, llmErr = rr.replier.complete(userPrompt, rr.replier.cfg.SystemMessage)
+			replyText, llmErr = rr.replier.complete(userPrompt, systemPrompt)
 		}
 	} else {
-		replyText, llmErr = rr.replier.complete(userPrompt, rr.replier.cfg.SystemMessage)
+		replyText, llmErr = rr.replier.complete(userPrompt, systemPrompt)
 	}
 	if llmErr != nil {
 		metrics.DefaultRecorder.RecordLLMError(metrics.OperationReply, rr.replier.cfg.ActiveProvider)
@@ -119,6 +207,25 @@ func (rr *ReviewReplier) Reply(d client.DiscussionInFileDTO, botUserID string) (
 	return &result, nil
 }
 
+// systemPrompt renders a reply system message template for the review.
+// The review diff and commit messages are only loaded for templates that reference them.
+func (rr *ReviewReplier) systemPrompt(tmpl *replyTemplate, botUserID string) (string, error) {
+	var diff string
+	if tmpl.usesDiff {
+		var err error
+		if diff, err = rr.loadCodeContext(); err != nil {
+			return "", err
+		}
+	}
+
+	systemPrompt, err := tmpl.Execute(newPromptData(rr.review, diff, rr.commits, 0, botUserID, rr.replier.cfg.Users))
+	if err != nil {
+		return "", fmt.Errorf("failed to render reply system message: %w", err)
+	}
+
+	return systemPrompt, nil
+}
+
 func (r *Replier) complete(userPrompt, systemPrompt string) (string, error) {
 	return r.llmProvider.Completion(userPrompt, systemPrompt)
 }
@@ -128,18 +235,26 @@ func (rr *ReviewReplier) loadCodeContext() (string, error) {
 		return rr.codeContext, nil
 	}
 
-	codeContext, _, err := rr.replier.gitProvider.GetReviewChanges(rr.review)
+	codeContext, commits, err := rr.replier.gitProvider.GetReviewChanges(rr.review)
 	if err != nil {
 		return "", fmt.Errorf("get review changes: %w", err)
 	}
 
 	rr.codeContext = codeContext
+	rr.commits = commits
 	rr.loaded = true
 	return rr.codeContext, nil
 }
 
-func buildReplyPromptParts(codeContext, anchorText, threadText string) (fullPrompt, prefix, suffix string) {
+// buildReplyPromptParts splits the reply prompt into a prefix that stays the same across the replies
+// in a discussion, the code context, anchor and thread summary, and a suffix with the latest messages.
+func buildReplyPromptParts(codeContext, anchorText, summary, threadText string) (fullPrompt, prefix, suffix string) {
 	prefix = fmt.Sprintf(replyUserPromptPrefixTemplate, codeContext, anchorText)
+	if summary != "" {
+		prefix += fmt.Sprintf(replySummarizedThreadTemplate, summary)
+	} else {
+		prefix += replyThreadHeader
+	}
 	suffix = threadText + "\n"
 	return prefix + suffix, prefix, suffix
 }
@@ -170,11 +285,13 @@ func parseLLMDiscissionReply(content string) string {
 	return ""
 }
 
-func buildThreadTranscript(comments []client.CommentDTO, botUserID string) []CommentMsg {
+// buildThreadTranscript converts discussion comments to transcript messages.
+// Authors are shown by their name, or by their user ID when the name is unknown.
+func buildThreadTranscript(comments []client.CommentDTO, botUserID string, names map[string]string) []CommentMsg {
 	out := make([]CommentMsg, 0, len(comments))
 	for _, c := range comments {
 		out = append(out, CommentMsg{
-			Author: c.AuthorID,
+			Author: displayName(names, c.AuthorID),
 			IsBot:  c.AuthorID == botUserID,
 			Text:   c.Text,
 		})
@@ -58,10 +58,10 @@ func TestReplyFallsBackWhenPrefixCacheFails(t *testing.T) {
 		ctx:         context.Background(),
 	}
 
-	replier := NewReplier(reviewer, ReplyConfig{
+	replier := mustNewReplier(t, reviewer, ReplyConfig{
 		SystemMessage:  "reply system",
 		ActiveProvider: config.ProviderOpenAI,
-	}).ForReview(&upsource.Review{})
+	}, nil).ForReview(&upsource.Review{})
 
 	result, err := replier.Reply(
 		client.DiscussionInFileDTO{
@@ -91,10 +91,10 @@ func TestReplyReturnsErrorWhenPrefixAndFallbackFail(t *testing.T) {
 		ctx:         context.Background(),
 	}
 
-	replier := NewReplier(reviewer, ReplyConfig{
+	replier := mustNewReplier(t, reviewer, ReplyConfig{
 		SystemMessage:  "reply system",
 		ActiveProvider: config.ProviderOpenAI,
-	}).ForReview(&upsource.Review{})
+	}, nil).ForReview(&upsource.Review{})
 
 	result, err := replier.Reply(
 		client.DiscussionInFileDTO{
@@ -107,3 +107,39 @@ func TestReplyReturnsErrorWhenPrefixAndFallbackFail(t *testing.T) {
 	require.Equal(t, 1, provider.prefixCalls)
 	require.Equal(t, 1, provider.completionCalls)
 }
+
+func TestNewReplierRejectsInvalidTemplates(t *testing.T) {
+	_, err := NewReplier(&Reviewer{}, ReplyConfig{MentionSystemMessage: "{{.Title"}, nil)
+	require.ErrorContains(t, err, "replies.mentions.systemMessage")
+}
+
+func TestReplySystemPromptGetsReviewDiff(t *testing.T) {
+	provider := &recordingProvider{response: `{"comment":"ok","close":false}`}
+	reviewer := &Reviewer{
+		llmProvider: provider,
+		gitProvider: &replierMockGitProvider{changes: mentionTestDiff},
+		ctx:         context.Background(),
+	}
+
+	_, err := mustNewReplier(t, reviewer, ReplyConfig{SystemMessage: "Diff:\n{{.Diff}}"}, nil).ForReview(&upsource.Review{}).Reply(
+		client.DiscussionInFileDTO{
+			Anchor:   client.AnchorDTO{FileID: "/b.go"},
+			Comments: []client.CommentDTO{{AuthorID: "dev", Text: "Why?"}},
+		},
+		"bot",
+	)
+	require.NoError(t, err)
+
+	// The template gets the whole review diff, while the code context is only the diff of b.go.
+	require.Equal(t, "Diff:\n"+mentionTestDiff, provider.systemPrompt)
+	require.NotContains(t, provider.userPrompt, "package aa")
+}
+
+func mustNewReplier(t *testing.T, reviewer *Reviewer, cfg ReplyConfig, anchors AnchorResolver) *Replier {
+	t.Helper()
+
+	replier, err := NewReplier(reviewer, cfg, anchors)
+	require.NoError(t, err)
+
+	return replier
+}
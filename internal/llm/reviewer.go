@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"strconv"
 	"strings"
 
+	"github.com/groall/upsource-ai-reviewer/internal/analyzer"
 	"github.com/groall/upsource-ai-reviewer/internal/git"
 	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/internal/redact"
+	"github.com/groall/upsource-ai-reviewer/internal/tracker"
 	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
 	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
 )
 
@@ -19,6 +22,20 @@ type Reviewer struct {
 	gitProvider git.Provider
 	cfg         ReviewConfig
 	ctx         context.Context
+	redactor    *redact.Redactor
+	critic      *critic
+	summarizer  *summarizer
+	tracker     tracker.Client
+
+	systemTemplate     *prompt.Template
+	userPromptTemplate *prompt.Template
+}
+
+// ReviewResult is the outcome of reviewing a change.
+type ReviewResult struct {
+	Comments []*ReviewComment
+	// Summary is nil when the summary pass is disabled or failed.
+	Summary *ReviewSummary
 }
 
 // New creates a new LLM Reviewer instance.
@@ -30,26 +47,105 @@ func New(ctx context.Context, cfg ReviewConfig, providers config.Providers, gitP
 	}
 
 	var err error
+	if reviewer.systemTemplate, err = prompt.Parse("review.systemMessage", cfg.SystemMessage); err != nil {
+		return nil, fmt.Errorf("failed to parse system message template: %w", err)
+	}
+	if reviewer.userPromptTemplate, err = prompt.Parse("review.userPromptTemplate", cfg.UserPromptTemplate); err != nil {
+		return nil, fmt.Errorf("failed to parse user prompt template: %w", err)
+	}
+
 	reviewer.llmProvider, err = createLLMProvider(ctx, providers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM provider: %w", err)
 	}
 
+	if cfg.Redaction.Enabled {
+		reviewer.redactor, err = redact.New(cfg.Redaction)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create redactor: %w", err)
+		}
+		reviewer.llmProvider = newRedactingProvider(reviewer.llmProvider, reviewer.redactor)
+	}
+
+	if cfg.Critique.Enabled {
+		critiqueProvider := reviewer.llmProvider
+		critiqueProviderName := cfg.ActiveProvider
+		if cfg.Critique.Provider != "" || cfg.Critique.Model != "" {
+			if cfg.Critique.Provider != "" {
+				critiqueProviderName = cfg.Critique.Provider
+			}
+			critiqueProvider, err = createNamedLLMProvider(ctx, providers, critiqueProviderName, cfg.Critique.Model)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create critique LLM provider: %w", err)
+			}
+			if reviewer.redactor != nil {
+				critiqueProvider = newRedactingProvider(critiqueProvider, reviewer.redactor)
+			}
+		}
+		reviewer.critic = newCritic(critiqueProvider, cfg.Critique, critiqueProviderName)
+	}
+
+	if cfg.Tracker.Enabled {
+		reviewer.tracker, err = tracker.New(cfg.Tracker)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create issue tracker client: %w", err)
+		}
+	}
+
+	if cfg.Summary.Enabled {
+		reviewer.summarizer = newSummarizer(reviewer.llmProvider, cfg.Summary, cfg.ActiveProvider)
+	}
+
 	return reviewer, nil
 }
 
 // Do calls OpenAI Chat Completion API to review changes.
-func (c *Reviewer) Do(review *upsource.Review) ([]*ReviewComment, error) {
+// Static analyzer findings are filtered to the changed lines and, depending on the analyzer mode,
+// either included in the prompt or returned as additional comments.
+// When the summary pass is enabled, the result also carries the overall summary of the change.
+// botUserID keeps the bot out of the reviewers shown to the model; it may be empty.
+func (c *Reviewer) Do(review *upsource.Review, findings []analyzer.Finding, botUserID string) (*ReviewResult, error) {
 	changes, commitsComments, err := c.gitProvider.GetReviewChanges(review)
 	if err != nil {
 		return nil, fmt.Errorf("error getting review changes for %s: %w", review.GetBranch(), err)
 	}
 
+	if len(findings) > 0 {
+		findings = filterFindingsToDiff(changes, findings)
+		if c.cfg.MaxFindings > 0 && len(findings) > c.cfg.MaxFindings {
+			findings = findings[:c.cfg.MaxFindings]
+		}
+		log.Printf("%d static analyzer findings on changed lines of %s.\n", len(findings), review.GetBranch())
+	}
+
 	// Build a concise prompt and send to OpenAI-compatible API using SDK
-	userPrompt := strings.Replace(c.cfg.SystemMessage, "{{diffs}}", changes, -1)
-	userPrompt = strings.Replace(userPrompt, "{{messages}}", commitsComments, -1)
+	data := newPromptData(review, changes, commitsComments, c.cfg.MaxPerReview, botUserID, c.cfg.Users)
+	userPrompt, err := c.userPromptTemplate.Execute(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render user prompt: %w", err)
+	}
+	if !prompt.Uses(c.cfg.UserPromptTemplate, "Title") && !prompt.Uses(c.cfg.UserPromptTemplate, "Description") {
+		userPrompt = reviewContext(data) + userPrompt
+	}
+	if c.cfg.AnalyzerMode == config.AnalyzerModeLLM {
+		userPrompt += formatFindings(findings)
+	}
+	if c.tracker != nil {
+		keys := prompt.IssueKeys(append(data.Issues, commitsComments)...)
+		issues := tracker.Fetch(c.ctx, c.tracker, keys, c.cfg.Tracker.MaxIssues)
+		log.Printf("Including %d of %d linked issues in the prompt for %s.\n", len(issues), len(keys), review.GetBranch())
+		userPrompt += tracker.Format(issues, c.cfg.Tracker.MaxIssueLength)
+	}
+	if c.cfg.Suppressions != nil {
+		userPrompt += doNotReport(c.cfg.Suppressions, review.GetProjectID(), diffFiles(changes), c.cfg.MaxSuppressionExamples)
+	}
 
-	systemPrompt := strings.Replace(c.cfg.SystemMessage, "{{max_per_review}}", strconv.Itoa(c.cfg.MaxPerReview), -1)
+	systemPrompt, err := c.systemTemplate.Execute(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render system message: %w", err)
+	}
+	style := outputStyle(c.cfg.Style, review, commitsComments)
+	systemPrompt += promptFragmentsFor(diffFiles(changes), c.cfg.PromptFragments) + style
 
 	log.Print("Sending prompt to LLM...")
 
@@ -67,7 +163,26 @@ func (c *Reviewer) Do(review *upsource.Review) ([]*ReviewComment, error) {
 
 	comments = validateCommentsAgainstDiff(changes, comments)
 
-	return comments, nil
+	if c.critic != nil {
+		comments = c.critic.Critique(changes, comments)
+	}
+
+	if c.cfg.AnalyzerMode == config.AnalyzerModePost {
+		comments = append(comments, findingsToComments(findings)...)
+	}
+
+	if c.redactor != nil && c.cfg.Redaction.CommentOnSecrets {
+		comments = append(comments, secretComments(changes, c.redactor)...)
+	}
+
+	result := &ReviewResult{Comments: comments}
+	if c.summarizer != nil {
+		if result.Summary, err = c.summarizer.Summarize(changes, commitsComments, comments, style); err != nil {
+			log.Printf("Skipping summary for %s: %v\n", review.GetBranch(), err)
+		}
+	}
+
+	return result, nil
 }
 
 func (c *Reviewer) complete(userPrompt, systemPrompt string) (string, error) {
@@ -90,7 +205,7 @@ func processAndPostLLMResponse(llmResponse string) ([]*ReviewComment, error) {
 		return nil, nil
 	}
 
-	return comments, nil
+	return normalizeComments(comments), nil
 
 }
 
@@ -0,0 +1,127 @@
+package llm
+
+import (
+	"log"
+	"strings"
+	"unicode"
+)
+
+// snippetMatchThreshold is the minimal similarity for a line to be considered the snippet's location.
+const snippetMatchThreshold = 0.6
+
+// minContainedTokens is the minimal number of tokens the shorter of a snippet and a line needs for
+// containment to count. Otherwise trivial lines such as ")", "return" or "err != nil" would match any snippet.
+const minContainedTokens = 3
+
+// relocateBySnippet moves a comment to the added line that best matches its snippet when the
+// reported line does not match it. LLMs quote code reliably but are bad at counting lines.
+// A multi-line range is moved along with its start line.
+func relocateBySnippet(c *ReviewComment, addedLines map[int]string) {
+	target := firstSnippetLine(c.Snippet)
+	if target == "" || len(addedLines) == 0 {
+		return
+	}
+
+	current := 0.0
+	if text, ok := addedLines[c.LineNumber]; ok {
+		current = snippetSimilarity(target, text)
+	}
+	if current == 1 {
+		return
+	}
+
+	bestLine, best := 0, 0.0
+	for line, text := range addedLines {
+		score := snippetSimilarity(target, text)
+		if score < best || (score == best && bestLine != 0 && !closer(line, bestLine, c.LineNumber)) {
+			continue
+		}
+		bestLine, best = line, score
+	}
+
+	if best < snippetMatchThreshold || best <= current {
+		return
+	}
+
+	log.Printf("Relocated comment on %s from line %d to %d by its snippet\n", c.FilePath, c.LineNumber, bestLine)
+	if c.EndLineNumber > c.LineNumber {
+		c.EndLineNumber += bestLine - c.LineNumber
+	}
+	c.LineNumber = bestLine
+}
+
+// firstSnippetLine returns the first non-blank line of a snippet with whitespace collapsed.
+func firstSnippetLine(snippet string) string {
+	for _, line := range strings.Split(snippet, "\n") {
+		if normalized := normalizeCodeLine(line); normalized != "" {
+			return normalized
+		}
+	}
+
+	return ""
+}
+
+// snippetSimilarity scores how well a line matches a snippet line, from 0 to 1.
+// Whitespace differences are ignored, a line containing the snippet (or vice versa) scores 0.9
+// when the contained side has at least minContainedTokens tokens, otherwise the share of common tokens is used.
+func snippetSimilarity(snippet, line string) float64 {
+	snippet = normalizeCodeLine(snippet)
+	line = normalizeCodeLine(line)
+
+	switch {
+	case snippet == "" || line == "":
+		return 0
+	case snippet == line:
+		return 1
+	}
+
+	a, b := codeTokens(snippet), codeTokens(line)
+	if strings.Contains(line, snippet) && len(a) >= minContainedTokens || strings.Contains(snippet, line) && len(b) >= minContainedTokens {
+		return 0.9
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	var common int
+	for token := range a {
+		if b[token] {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func normalizeCodeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+func codeTokens(line string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, token := range strings.FieldsFunc(line, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		tokens[token] = true
+	}
+
+	return tokens
+}
+
+// closer reports whether line a is closer to ref than line b, preferring the earlier line on a tie.
+func closer(a, b, ref int) bool {
+	da, db := distance(a, ref), distance(b, ref)
+	if da != db {
+		return da < db
+	}
+
+	return a < b
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
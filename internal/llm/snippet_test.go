@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCommentsAgainstDiffRelocatesBySnippet(t *testing.T) {
+	comments := validateCommentsAgainstDiff(suggestionTestDiff, []*ReviewComment{
+		{FilePath: "pkg/a.go", LineNumber: 11, Snippet: "z := y * 2"},
+		{FilePath: "pkg/a.go", LineNumber: 0, Snippet: "  w := 3\n"},
+		{FilePath: "pkg/a.go", LineNumber: 20, EndLineNumber: 21, Snippet: "y := 2\nz := y * 2"},
+		{FilePath: "pkg/a.go", LineNumber: 11, Snippet: "return nil"},
+	})
+
+	require.Equal(t, 12, comments[0].LineNumber)
+	require.True(t, comments[0].LineVerified)
+
+	require.Equal(t, 14, comments[1].LineNumber)
+	require.True(t, comments[1].LineVerified)
+
+	require.Equal(t, 11, comments[2].LineNumber)
+	require.Equal(t, 12, comments[2].EndLineNumber)
+	require.True(t, comments[2].LineVerified)
+
+	require.Equal(t, 11, comments[3].LineNumber, "a snippet without a match keeps the reported line")
+}
+
+func TestSnippetSimilarity(t *testing.T) {
+	require.Equal(t, 1.0, snippetSimilarity("a := b", "\ta  :=  b"))
+	require.Equal(t, 0.9, snippetSimilarity("doIt(ctx, id)", "if err := doIt(ctx, id); err != nil {"))
+	require.Less(t, snippetSimilarity("doIt()", "if err := doIt(); err != nil {"), snippetMatchThreshold)
+	require.Zero(t, snippetSimilarity("result, err := fetch(ctx, id, opts)", ")"))
+	require.Less(t, snippetSimilarity("if err != nil { return err }", "err != nil"), snippetMatchThreshold)
+	require.InDelta(t, 0.5, snippetSimilarity("user.Name = name", "user.Email = name"), 1e-9)
+	require.Zero(t, snippetSimilarity("", "x"))
+}
+
+func TestRelocateBySnippetIgnoresTrivialLines(t *testing.T) {
+	comment := &ReviewComment{FilePath: "a.go", LineNumber: 11, Snippet: "res, err := client.Fetch(ctx, id, opts)"}
+	relocateBySnippet(comment, map[int]string{
+		10: "\tresult, err := client.Fetch(ctx, id,",
+		11: "\t\toptions,",
+		12: "\t)",
+		13: "\tif err != nil {",
+		14: "\t\treturn err",
+	})
+
+	require.Equal(t, 10, comment.LineNumber)
+}
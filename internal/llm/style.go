@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+var toneInstructions = map[string]string{
+	config.ToneConcise: "Be brief and direct: state the problem and the fix in one or two sentences, without praise or filler.",
+	config.ToneMentoring: "Explain the reasoning behind each point so that a less experienced developer learns from it, " +
+		"and show how to do it better. Stay friendly and encouraging.",
+	config.ToneStrict: "Hold the code to a high standard: point out every deviation from good practice, " +
+		"name the risk plainly and do not soften what must be changed.",
+}
+
+// outputStyle returns the system prompt section with the language and tone of the text posted in a review,
+// or "" when neither is configured. The language is detected from the review title, description
+// and texts such as commit messages when it is set to auto.
+func outputStyle(style config.Style, review *upsource.Review, texts ...string) string {
+	language, tone := style.For(review.GetProjectID())
+	if language == config.LanguageAuto {
+		language = detectLanguage(append([]string{review.GetTitle(), review.GetDescription()}, texts...)...)
+	}
+	if language == "" && tone == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n### Output style\n")
+	if language != "" {
+		_, _ = fmt.Fprintf(&b, "\nWrite all text meant for developers (comments, summaries, replies) in %s. Keep code, identifiers and JSON keys as they are.\n", language)
+	}
+	if instructions, ok := toneInstructions[tone]; ok {
+		b.WriteString("\n" + instructions + "\n")
+	}
+
+	return b.String()
+}
+
+// scriptLanguages names the language of texts written mostly in a script other than Latin.
+var scriptLanguages = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Hiragana, "Japanese"},
+	{unicode.Katakana, "Japanese"},
+	{unicode.Hangul, "Korean"},
+	{unicode.Han, "Chinese"},
+	{unicode.Cyrillic, "Russian"},
+	{unicode.Greek, "Greek"},
+	{unicode.Arabic, "Arabic"},
+	{unicode.Hebrew, "Hebrew"},
+}
+
+// latinStopWords are frequent words that tell apart languages written in the Latin script.
+var latinStopWords = map[string][]string{
+	"English":    {"the", "and", "for", "with", "this", "that", "from", "when", "not", "add", "fix", "remove", "use"},
+	"German":     {"der", "die", "das", "und", "mit", "für", "nicht", "ist", "auf", "bei", "wird", "hinzufügen"},
+	"French":     {"le", "la", "les", "et", "pour", "avec", "dans", "des", "une", "pas", "est", "ajout"},
+	"Spanish":    {"el", "los", "las", "y", "para", "con", "del", "una", "por", "que", "se", "agregar"},
+	"Portuguese": {"o", "os", "as", "e", "para", "com", "do", "da", "uma", "não", "em", "adicionar"},
+	"Italian":    {"il", "lo", "gli", "e", "per", "con", "della", "una", "non", "che", "aggiungi"},
+	"Dutch":      {"de", "het", "een", "en", "voor", "met", "niet", "van", "toevoegen"},
+}
+
+// detectLanguage guesses the natural language of texts such as the review title and commit messages.
+// Texts mostly in a non-Latin script are named by the script; Latin texts by the most frequent stop words.
+// It returns "" when there is not enough evidence.
+func detectLanguage(texts ...string) string {
+	text := strings.Join(texts, "\n")
+
+	var letters, latin int
+	scripts := make(map[string]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.table, r) {
+				scripts[s.language]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+
+	// Japanese mixes kana with Han characters, so any kana decides between the two.
+	if scripts["Japanese"] > 0 && scripts["Japanese"]+scripts["Chinese"] > latin {
+		return "Japanese"
+	}
+	best, bestCount := "", 0
+	for language, count := range scripts {
+		if count > bestCount || count == bestCount && language < best {
+			best, bestCount = language, count
+		}
+	}
+	if bestCount > latin {
+		if best == "Russian" && strings.ContainsAny(strings.ToLower(text), "іїєґ") {
+			return "Ukrainian"
+		}
+		return best
+	}
+
+	return detectLatinLanguage(text)
+}
+
+func detectLatinLanguage(text string) string {
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for language, stopWords := range latinStopWords {
+			for _, stopWord := range stopWords {
+				if word == stopWord {
+					counts[language]++
+					break
+				}
+			}
+		}
+	}
+
+	best, bestCount, tie := "", 0, false
+	for language, count := range counts {
+		switch {
+		case count > bestCount:
+			best, bestCount, tie = language, count, false
+		case count == bestCount:
+			tie = true
+		}
+	}
+	if bestCount < 2 || tie {
+		return ""
+	}
+
+	return best
+}
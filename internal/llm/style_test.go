@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+	"github.com/groall/upsource-go-client/client"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  string
+	}{
+		{texts: []string{"Add retry for the upload client", "Fix timeout when the server is slow"}, want: "English"},
+		{texts: []string{"Fehlerbehandlung für den Upload hinzufügen", "Timeout wird nicht mehr ignoriert und ist konfigurierbar"}, want: "German"},
+		{texts: []string{"Добавить повторные попытки для клиента загрузки"}, want: "Russian"},
+		{texts: []string{"Додати повторні спроби для клієнта"}, want: "Ukrainian"},
+		{texts: []string{"アップロードのリトライを追加"}, want: "Japanese"},
+		{texts: []string{"添加上传重试"}, want: "Chinese"},
+		{texts: []string{"PROJ-123", "refactoring"}, want: ""},
+		{texts: []string{""}, want: ""},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, detectLanguage(tt.texts...), tt.texts)
+	}
+}
+
+func TestOutputStyle(t *testing.T) {
+	require.Empty(t, outputStyle(config.Style{}, &upsource.Review{}))
+
+	got := outputStyle(config.Style{Language: "German", Tone: config.ToneStrict}, &upsource.Review{})
+	require.Contains(t, got, "### Output style")
+	require.Contains(t, got, "in German.")
+	require.Contains(t, got, toneInstructions[config.ToneStrict])
+
+	got = outputStyle(config.Style{Language: config.LanguageAuto}, &upsource.Review{}, "Fix the timeout when the server is slow")
+	require.Contains(t, got, "in English.")
+	require.NotContains(t, got, toneInstructions[config.ToneConcise])
+
+	// Nothing is detected, so there is nothing to say.
+	require.Empty(t, outputStyle(config.Style{Language: config.LanguageAuto}, &upsource.Review{}, "PROJ-123"))
+}
+
+func TestReplyUsesOutputStyle(t *testing.T) {
+	provider := &recordingProvider{response: `{"comment":"Ja."}`}
+	reviewer := &Reviewer{llmProvider: provider, gitProvider: &replierMockGitProvider{changes: mentionTestDiff}, ctx: context.Background()}
+	style := config.Style{Tone: config.ToneConcise, Projects: map[string]config.ProjectStyle{"": {Language: "German"}}}
+
+	_, err := mustNewReplier(t, reviewer, ReplyConfig{Style: style}, nil).ForReview(&upsource.Review{}).AnswerMention(
+		client.DiscussionInFileDTO{Comments: []client.CommentDTO{{AuthorID: "dev", Text: "@bot is this safe?"}}},
+		"bot",
+	)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(provider.systemPrompt, defaultMentionSystemMessage))
+	require.Contains(t, provider.systemPrompt, "in German.")
+	require.Contains(t, provider.systemPrompt, toneInstructions[config.ToneConcise])
+}
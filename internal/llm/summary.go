@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+const (
+	VerdictLooksGood = "looks good"
+	VerdictNeedsWork = "needs work"
+	VerdictBlocking  = "blocking"
+)
+
+const defaultSummarySystemMessage = `You are a senior engineer writing the overall summary of a code review for the team lead.
+
+Using the diff, the commit messages and the review comments already found, describe:
+* description: what the change does, in two or three sentences,
+* riskLevel: how risky the change is to deploy ("low", "medium" or "high"),
+* riskAreas: the parts of the change most likely to break something,
+* testCoverage: concerns about missing or insufficient tests, empty if there are none,
+* verdict: "looks good", "needs work" or "blocking".
+
+Respond ONLY with a JSON object:
+{"description": "<text>", "riskLevel": "low"|"medium"|"high", "riskAreas": ["<text>"], "testCoverage": "<text>", "verdict": "looks good"|"needs work"|"blocking"}`
+
+const summaryUserPromptTemplate = `### Diff:
+
+%s
+
+### Commit messages:
+
+%s
+
+### Review comments:
+
+%s
+`
+
+// ReviewSummary is the overall assessment of a review.
+type ReviewSummary struct {
+	Description  string   `json:"description"`
+	RiskLevel    string   `json:"riskLevel"`
+	RiskAreas    []string `json:"riskAreas"`
+	TestCoverage string   `json:"testCoverage"`
+	Verdict      string   `json:"verdict"`
+}
+
+// summarizer runs the summary pass over a reviewed change.
+type summarizer struct {
+	llmProvider    Provider
+	cfg            config.Summary
+	activeProvider string
+}
+
+func newSummarizer(llmProvider Provider, cfg config.Summary, activeProvider string) *summarizer {
+	if cfg.SystemMessage == "" {
+		cfg.SystemMessage = defaultSummarySystemMessage
+	}
+
+	return &summarizer{
+		llmProvider:    llmProvider,
+		cfg:            cfg,
+		activeProvider: activeProvider,
+	}
+}
+
+// Summarize asks the LLM for the overall summary of the change, taking the final review comments into account.
+// style is appended to the system message, see outputStyle.
+func (s *summarizer) Summarize(diff, commitsComments string, comments []*ReviewComment, style string) (*ReviewSummary, error) {
+	encoded, err := json.Marshal(comments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode review comments: %w", err)
+	}
+
+	log.Print("Sending summary prompt to LLM...")
+
+	response, err := s.llmProvider.Completion(fmt.Sprintf(summaryUserPromptTemplate, diff, commitsComments, encoded), s.cfg.SystemMessage+style)
+	if err != nil {
+		metrics.DefaultRecorder.RecordLLMError(metrics.OperationSummary, s.activeProvider)
+		return nil, fmt.Errorf("LLM request failed: %w", err)
+	}
+
+	var summary ReviewSummary
+	if err := json.Unmarshal([]byte(parseLLMDiscissionReply(response)), &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM JSON response: %w", err)
+	}
+
+	return normalizeSummary(&summary), nil
+}
+
+// normalizeSummary lowercases the risk level and the verdict and clears values outside the known sets.
+func normalizeSummary(summary *ReviewSummary) *ReviewSummary {
+	summary.RiskLevel = strings.ToLower(strings.TrimSpace(summary.RiskLevel))
+	if SeverityRank(summary.RiskLevel) > 2 {
+		summary.RiskLevel = ""
+	}
+
+	summary.Verdict = strings.Join(strings.FieldsFunc(strings.ToLower(summary.Verdict), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}), " ")
+	switch summary.Verdict {
+	case VerdictLooksGood, VerdictNeedsWork, VerdictBlocking:
+	default:
+		log.Printf("Unknown summary verdict %q\n", summary.Verdict)
+		summary.Verdict = ""
+	}
+
+	return summary
+}
@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+func TestSummarize(t *testing.T) {
+	provider := &mockProvider{CompletionFunc: func(userPrompt, systemPrompt string) (string, error) {
+		require.Equal(t, defaultSummarySystemMessage, systemPrompt)
+		require.Contains(t, userPrompt, "+x := 1")
+		require.Contains(t, userPrompt, "Add x")
+		require.Contains(t, userPrompt, `"comment":"nil deref"`)
+		return "Here is the summary:\n" + `{
+			"description": "Adds x.",
+			"riskLevel": "Medium",
+			"riskAreas": ["initialisation order"],
+			"testCoverage": "No tests for x.",
+			"verdict": "Needs_Work"
+		}`, nil
+	}}
+
+	summary, err := newSummarizer(provider, config.Summary{}, config.ProviderOpenAI).
+		Summarize("+x := 1", "Add x", []*ReviewComment{{Comment: "nil deref", Severity: SeverityHigh}}, "")
+
+	require.NoError(t, err)
+	require.Equal(t, &ReviewSummary{
+		Description:  "Adds x.",
+		RiskLevel:    SeverityMedium,
+		RiskAreas:    []string{"initialisation order"},
+		TestCoverage: "No tests for x.",
+		Verdict:      VerdictNeedsWork,
+	}, summary)
+}
+
+func TestSummarizeClearsUnknownValues(t *testing.T) {
+	provider := &mockProvider{CompletionFunc: func(userPrompt, systemPrompt string) (string, error) {
+		return `{"description": "d", "riskLevel": "extreme", "verdict": "ship it"}`, nil
+	}}
+
+	summary, err := newSummarizer(provider, config.Summary{}, config.ProviderOpenAI).Summarize("diff", "", nil, "")
+
+	require.NoError(t, err)
+	require.Empty(t, summary.RiskLevel)
+	require.Empty(t, summary.Verdict)
+}
+
+func TestSummarizeReturnsErrorWhenLLMFails(t *testing.T) {
+	provider := &mockProvider{CompletionFunc: func(userPrompt, systemPrompt string) (string, error) {
+		return "", errors.New("boom")
+	}}
+
+	_, err := newSummarizer(provider, config.Summary{}, config.ProviderOpenAI).Summarize("diff", "", nil, "")
+
+	require.EqualError(t, err, "LLM request failed: boom")
+}
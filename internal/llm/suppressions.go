@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+)
+
+// SuppressionSource returns the active suppressions that apply to a file of a project.
+type SuppressionSource interface {
+	Suppressions(projectID, path string) []store.Suppression
+}
+
+// doNotReport lists up to maxExamples suppressions that apply to the changed files as findings
+// the model must not report. File-scoped suppressions come first.
+func doNotReport(source SuppressionSource, projectID string, files []string, maxExamples int) string {
+	seen := make(map[string]bool)
+	var fileScoped, projectScoped []store.Suppression
+	for _, file := range files {
+		for _, sup := range source.Suppressions(projectID, file) {
+			if seen[sup.ID] {
+				continue
+			}
+			seen[sup.ID] = true
+
+			if sup.Path == "" {
+				projectScoped = append(projectScoped, sup)
+			} else {
+				fileScoped = append(fileScoped, sup)
+			}
+		}
+	}
+
+	examples := append(fileScoped, projectScoped...)
+	if len(examples) == 0 {
+		return ""
+	}
+	if maxExamples > 0 && len(examples) > maxExamples {
+		examples = examples[:maxExamples]
+	}
+
+	var b strings.Builder
+	b.WriteString("\n### Do not report\n\nThe developers rejected these kinds of findings before. Do not report them or similar issues again:\n\n")
+	for _, sup := range examples {
+		scope := "anywhere in the project"
+		if sup.Path != "" {
+			scope = fmt.Sprintf("in `%s`", sup.Path)
+		}
+		_, _ = fmt.Fprintf(&b, "- %s: %s\n", scope, sup.Text)
+	}
+
+	return b.String()
+}
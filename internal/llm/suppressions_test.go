@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+)
+
+type fakeSuppressionSource []store.Suppression
+
+func (f fakeSuppressionSource) Suppressions(projectID, path string) []store.Suppression {
+	var out []store.Suppression
+	for _, sup := range f {
+		if sup.Matches(projectID, path) {
+			out = append(out, sup)
+		}
+	}
+	return out
+}
+
+func TestDoNotReport(t *testing.T) {
+	source := fakeSuppressionSource{
+		{ID: "s1", ProjectID: "proj", Text: "Errors from Close are ignored on purpose."},
+		{ID: "s2", ProjectID: "proj", Path: "b.go", Text: "Magic numbers in tests are fine."},
+		{ID: "s3", ProjectID: "other", Text: "Unrelated."},
+	}
+
+	got := doNotReport(source, "proj", []string{"a.go", "b.go"}, 10)
+	require.Equal(t, "\n### Do not report\n\nThe developers rejected these kinds of findings before. Do not report them or similar issues again:\n\n"+
+		"- in `b.go`: Magic numbers in tests are fine.\n"+
+		"- anywhere in the project: Errors from Close are ignored on purpose.\n", got)
+
+	require.NotContains(t, doNotReport(source, "proj", []string{"a.go", "b.go"}, 1), "Close")
+	require.Empty(t, doNotReport(source, "empty", []string{"a.go"}, 10))
+}
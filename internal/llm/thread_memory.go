@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-go-client/client"
+)
+
+const defaultThreadSummarySystemMessage = `You summarise the older part of a code review discussion between developers and an AI reviewer,
+so the discussion can continue without the full transcript.
+
+Extend the earlier summary, if there is one, with the new messages. Keep the issue that was raised,
+the arguments of each participant, attributed by name, what was agreed or decided, open questions,
+and the code, files and names referred to. Do not add opinions of your own.
+
+Respond with plain text of at most 200 words.`
+
+const threadSummaryUserPromptTemplate = `### Summary of the earlier discussion
+%s
+
+### Messages to add to the summary (oldest first)
+%s
+`
+
+// ThreadMemory persists the summaries of long discussion threads.
+type ThreadMemory interface {
+	ThreadSummary(discussionID string) (store.ThreadSummary, bool)
+	SetThreadSummary(summary store.ThreadSummary) error
+}
+
+// UserNames resolves Upsource user IDs to display names. IDs without a known name are left out.
+type UserNames interface {
+	UserNames(userIDs []string) map[string]string
+}
+
+// threadTranscript builds the transcript of a discussion with the display names of its authors.
+func (rr *ReviewReplier) threadTranscript(d client.DiscussionInFileDTO, botUserID string) []CommentMsg {
+	var names map[string]string
+	if users := rr.replier.cfg.Users; users != nil {
+		seen := make(map[string]bool)
+		var ids []string
+		for _, c := range d.Comments {
+			if !seen[c.AuthorID] {
+				seen[c.AuthorID] = true
+				ids = append(ids, c.AuthorID)
+			}
+		}
+		names = users.UserNames(ids)
+	}
+
+	return buildThreadTranscript(d.Comments, botUserID, names)
+}
+
+// threadPrompt returns the summary of the older messages of a discussion and the transcript of the rest.
+// Once the verbatim part of a thread exceeds the token limit, all but the latest messages are folded
+// into the stored summary. The summary is kept until the thread grows past the limit again,
+// so it stays in the cached part of the prompt across replies.
+func (rr *ReviewReplier) threadPrompt(d client.DiscussionInFileDTO, botUserID string) (summary, thread string) {
+	msgs := rr.threadTranscript(d, botUserID)
+	memory, threads := rr.replier.cfg.Memory, rr.replier.cfg.Threads
+	if !memory.Enabled || threads == nil {
+		return "", formatThread(msgs)
+	}
+
+	covered := 0
+	if stored, ok := threads.ThreadSummary(d.DiscussionID); ok && summaryCovers(stored, d.Comments) {
+		covered, summary = stored.Covered, stored.Text
+	}
+
+	recent := msgs[covered:]
+	if len(recent) <= memory.KeepRecent || estimateTokens(formatThread(recent)) <= memory.MaxThreadTokens {
+		return summary, formatThread(recent)
+	}
+
+	upTo := len(msgs) - memory.KeepRecent
+	updated, err := rr.summarizeThread(summary, msgs[covered:upTo], botUserID)
+	if err != nil {
+		log.Printf("Failed to summarise discussion %s, sending it in full: %v\n", d.DiscussionID, err)
+		return summary, formatThread(recent)
+	}
+
+	err = threads.SetThreadSummary(store.ThreadSummary{
+		DiscussionID:  d.DiscussionID,
+		Covered:       upTo,
+		LastCommentID: d.Comments[upTo-1].CommentID,
+		Text:          updated,
+	})
+	if err != nil {
+		log.Printf("Failed to store the summary of discussion %s: %v\n", d.DiscussionID, err)
+	}
+	log.Printf("Summarised %d messages of discussion %s\n", upTo, d.DiscussionID)
+
+	return updated, formatThread(msgs[upTo:])
+}
+
+// summarizeThread asks the LLM to extend the summary of a thread with older messages.
+func (rr *ReviewReplier) summarizeThread(summary string, msgs []CommentMsg, botUserID string) (string, error) {
+	systemPrompt, err := rr.systemPrompt(rr.replier.templates.threadSummary, botUserID)
+	if err != nil {
+		return "", err
+	}
+
+	if summary == "" {
+		summary = "(none)"
+	}
+
+	log.Print("Sending thread summary prompt to LLM...")
+
+	response, err := rr.replier.complete(fmt.Sprintf(threadSummaryUserPromptTemplate, summary, formatThread(msgs)), systemPrompt)
+	if err != nil {
+		metrics.DefaultRecorder.RecordLLMError(metrics.OperationReply, rr.replier.cfg.ActiveProvider)
+		return "", fmt.Errorf("LLM thread summary request failed: %w", err)
+	}
+
+	response = strings.TrimSpace(response)
+	if response == "" {
+		return "", errors.New("LLM returned an empty thread summary")
+	}
+
+	return response, nil
+}
+
+// summaryCovers reports whether a stored summary still matches the beginning of the thread.
+func summaryCovers(summary store.ThreadSummary, comments []client.CommentDTO) bool {
+	return summary.Covered > 0 && summary.Covered <= len(comments) && comments[summary.Covered-1].CommentID == summary.LastCommentID
+}
+
+// estimateTokens roughly estimates the number of tokens of a text at four characters per token.
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+	"github.com/groall/upsource-go-client/client"
+)
+
+type fakeThreadMemory map[string]store.ThreadSummary
+
+func (m fakeThreadMemory) ThreadSummary(discussionID string) (store.ThreadSummary, bool) {
+	s, ok := m[discussionID]
+	return s, ok
+}
+
+func (m fakeThreadMemory) SetThreadSummary(summary store.ThreadSummary) error {
+	m[summary.DiscussionID] = summary
+	return nil
+}
+
+type fakeUserNames map[string]string
+
+func (n fakeUserNames) UserNames(userIDs []string) map[string]string {
+	out := make(map[string]string)
+	for _, id := range userIDs {
+		if name, ok := n[id]; ok {
+			out[id] = name
+		}
+	}
+	return out
+}
+
+func longDiscussion(n int) client.DiscussionInFileDTO {
+	d := client.DiscussionInFileDTO{DiscussionID: "d1"}
+	for i := 1; i <= n; i++ {
+		author := "bot"
+		if i%2 == 0 {
+			author = "u1"
+		}
+		d.Comments = append(d.Comments, client.CommentDTO{
+			CommentID: fmt.Sprintf("c%d", i),
+			AuthorID:  author,
+			Text:      fmt.Sprintf("Message %d %s", i, strings.Repeat("x", 40)),
+		})
+	}
+	return d
+}
+
+func TestThreadPrompt(t *testing.T) {
+	memory := fakeThreadMemory{}
+	provider := &recordingProvider{response: "  Alice disagrees with the naming.  "}
+	cfg := ReplyConfig{
+		Memory:  config.Memory{Enabled: true, MaxThreadTokens: 60, KeepRecent: 2},
+		Threads: memory,
+		Users:   fakeUserNames{"u1": "Alice", "bot": "AI Bot"},
+	}
+	rr := mustNewReplier(t, &Reviewer{llmProvider: provider}, cfg, nil).ForReview(&upsource.Review{})
+
+	t.Run("short threads are sent verbatim", func(t *testing.T) {
+		summary, thread := rr.threadPrompt(longDiscussion(1), "bot")
+		require.Empty(t, summary)
+		require.True(t, strings.HasPrefix(thread, "AI Reviewer (AI Bot):\nMessage 1"))
+		require.Empty(t, provider.userPrompt)
+	})
+
+	t.Run("older messages are summarised", func(t *testing.T) {
+		summary, thread := rr.threadPrompt(longDiscussion(6), "bot")
+		require.Equal(t, "Alice disagrees with the naming.", summary)
+		require.True(t, strings.HasPrefix(thread, "AI Reviewer (AI Bot):\nMessage 5"))
+		require.Contains(t, thread, "Human (Alice):\nMessage 6")
+		require.NotContains(t, thread, "Message 4")
+		require.Equal(t, defaultThreadSummarySystemMessage, provider.systemPrompt)
+		require.Contains(t, provider.userPrompt, "(none)")
+		require.Contains(t, provider.userPrompt, "Message 4")
+		require.NotContains(t, provider.userPrompt, "Message 5")
+		require.Equal(t, store.ThreadSummary{DiscussionID: "d1", Covered: 4, LastCommentID: "c4", Text: summary}, memory["d1"])
+	})
+
+	t.Run("stored summary is reused", func(t *testing.T) {
+		provider.userPrompt = ""
+		summary, thread := rr.threadPrompt(longDiscussion(7), "bot")
+		require.Equal(t, "Alice disagrees with the naming.", summary)
+		require.True(t, strings.HasPrefix(thread, "AI Reviewer (AI Bot):\nMessage 5"))
+		require.Contains(t, thread, "Message 7")
+		require.Empty(t, provider.userPrompt)
+	})
+
+	t.Run("summary is extended once the thread grows again", func(t *testing.T) {
+		provider.response = "Alice still disagrees."
+		summary, thread := rr.threadPrompt(longDiscussion(9), "bot")
+		require.Equal(t, "Alice still disagrees.", summary)
+		require.True(t, strings.HasPrefix(thread, "Human (Alice):\nMessage 8"))
+		require.Contains(t, provider.userPrompt, "Alice disagrees with the naming.")
+		require.Contains(t, provider.userPrompt, "Message 5")
+		require.Equal(t, 7, memory["d1"].Covered)
+	})
+
+	t.Run("summary of a changed thread is discarded", func(t *testing.T) {
+		memory["d1"] = store.ThreadSummary{DiscussionID: "d1", Covered: 4, LastCommentID: "deleted", Text: "Stale"}
+		provider.response = "Fresh summary."
+		summary, _ := rr.threadPrompt(longDiscussion(6), "bot")
+		require.Equal(t, "Fresh summary.", summary)
+		require.Contains(t, provider.userPrompt, "Message 1")
+	})
+}
+
+func TestBuildReplyPromptPartsWithSummary(t *testing.T) {
+	full, prefix, suffix := buildReplyPromptParts("code", "anchor", "Earlier points.", "Human (Alice):\nStill wrong.")
+	require.Equal(t, prefix+suffix, full)
+	require.Contains(t, prefix, "### Summary of the earlier discussion\nEarlier points.")
+	require.True(t, strings.HasSuffix(prefix, "### Latest messages of the discussion (oldest first)\n"))
+	require.Equal(t, "Human (Alice):\nStill wrong.\n", suffix)
+
+	_, prefix, _ = buildReplyPromptParts("code", "anchor", "", "thread")
+	require.True(t, strings.HasSuffix(prefix, replyThreadHeader))
+}
@@ -15,8 +15,10 @@ import (
 )
 
 const (
-	OperationReview = "review"
-	OperationReply  = "reply"
+	OperationReview   = "review"
+	OperationReply    = "reply"
+	OperationCritique = "critique"
+	OperationSummary  = "summary"
 )
 
 type Recorder interface {
@@ -24,6 +26,9 @@ type Recorder interface {
 	RecordReplySent()
 	RecordReviewCommentsPosted(count int)
 	RecordLLMError(operation, currentProvider string)
+	RecordRedaction(detector string)
+	RecordCommentOutcome(model, severity, fileType, outcome string)
+	SetAcceptanceRate(model string, rate float64)
 }
 
 type prometheusRecorder struct{}
@@ -47,11 +52,23 @@ var (
 		Name: "upsource_ai_reviewer_llm_errors_total",
 		Help: "Total number of errors received from the current LLM provider.",
 	}, []string{"provider", "operation"})
+	redactionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "upsource_ai_reviewer_redactions_total",
+		Help: "Total number of values masked before sending text to the LLM provider.",
+	}, []string{"detector"})
+	commentOutcomesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "upsource_ai_reviewer_comment_outcomes_total",
+		Help: "Total number of classified responses to comments posted by the AI reviewer.",
+	}, []string{"model", "severity", "file_type", "outcome"})
+	acceptanceRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "upsource_ai_reviewer_comment_acceptance_rate",
+		Help: "Share of accepted comments among all classified comments of a model, including earlier runs.",
+	}, []string{"model"})
 )
 
 func init() {
 	for _, provider := range []string{"agent", "openai", "gemini", "anthropic"} {
-		for _, operation := range []string{OperationReview, OperationReply} {
+		for _, operation := range []string{OperationReview, OperationReply, OperationCritique, OperationSummary} {
 			llmErrorsTotal.WithLabelValues(provider, operation)
 		}
 	}
@@ -122,3 +139,15 @@ func (prometheusRecorder) RecordReviewCommentsPosted(count int) {
 func (prometheusRecorder) RecordLLMError(operation, currentProvider string) {
 	llmErrorsTotal.WithLabelValues(currentProvider, operation).Inc()
 }
+
+func (prometheusRecorder) RecordRedaction(detector string) {
+	redactionsTotal.WithLabelValues(detector).Inc()
+}
+
+func (prometheusRecorder) RecordCommentOutcome(model, severity, fileType, outcome string) {
+	commentOutcomesTotal.WithLabelValues(model, severity, fileType, outcome).Inc()
+}
+
+func (prometheusRecorder) SetAcceptanceRate(model string, rate float64) {
+	acceptanceRate.WithLabelValues(model).Set(rate)
+}
@@ -50,3 +50,30 @@ func counterValue(t *testing.T, metric prometheus.Metric) float64 {
 
 	return dtoMetric.Counter.GetValue()
 }
+
+func TestRecordRedaction(t *testing.T) {
+	emails := redactionsTotal.WithLabelValues("email")
+	before := counterValue(t, emails)
+
+	DefaultRecorder.RecordRedaction("email")
+	DefaultRecorder.RecordRedaction("email")
+
+	require.Equal(t, before+2, counterValue(t, emails))
+}
+
+func TestRecordCommentOutcome(t *testing.T) {
+	accepted := commentOutcomesTotal.WithLabelValues("openai/gpt", "high", "go", "accepted")
+	before := counterValue(t, accepted)
+
+	DefaultRecorder.RecordCommentOutcome("openai/gpt", "high", "go", "accepted")
+
+	require.Equal(t, before+1, counterValue(t, accepted))
+}
+
+func TestSetAcceptanceRate(t *testing.T) {
+	DefaultRecorder.SetAcceptanceRate("openai/gpt", 0.75)
+
+	var dtoMetric dto.Metric
+	require.NoError(t, acceptanceRate.WithLabelValues("openai/gpt").Write(&dtoMetric))
+	require.Equal(t, 0.75, dtoMetric.Gauge.GetValue())
+}
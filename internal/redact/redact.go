@@ -0,0 +1,211 @@
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+// Match describes a single redacted value. The value itself is never kept.
+type Match struct {
+	Detector string
+	Secret   bool // Credentials, as opposed to personal data.
+	Line     int  // 1-based line number within the redacted text.
+}
+
+type detector struct {
+	name   string
+	secret bool
+	re     *regexp.Regexp
+	// group is the capturing group to mask; 0 masks the whole match.
+	group int
+	// accept filters candidate values, e.g. by entropy.
+	accept func(value string) bool
+}
+
+// Redactor masks secrets and personal data in text sent to LLM providers.
+type Redactor struct {
+	detectors  []detector
+	privateKey bool
+}
+
+var (
+	privateKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`)
+	privateKeyEnd   = regexp.MustCompile(`-----END [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`)
+	privateKeyBody  = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9+/=]{15,}`)
+)
+
+// New creates a Redactor with the enabled built-in detectors and the custom patterns.
+func New(cfg config.Redaction) (*Redactor, error) {
+	r := &Redactor{
+		privateKey: cfg.DetectorEnabled(config.RedactionDetectorPrivateKey),
+	}
+
+	if cfg.DetectorEnabled(config.RedactionDetectorAWS) {
+		r.detectors = append(r.detectors,
+			detector{
+				name:   "aws-access-key",
+				secret: true,
+				re:     regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`),
+			},
+			detector{
+				name:   "aws-secret-key",
+				secret: true,
+				re:     regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|key).{0,20}?[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`),
+				group:  1,
+			},
+		)
+	}
+
+	if cfg.DetectorEnabled(config.RedactionDetectorJWT) {
+		r.detectors = append(r.detectors, detector{
+			name:   "jwt",
+			secret: true,
+			re:     regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{5,}`),
+		})
+	}
+
+	for _, p := range cfg.CustomPatterns {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %s: %w", p.Name, err)
+		}
+		d := detector{name: p.Name, secret: p.Secret, re: re}
+		if re.NumSubexp() > 0 {
+			d.group = 1
+		}
+		r.detectors = append(r.detectors, d)
+	}
+
+	if cfg.DetectorEnabled(config.RedactionDetectorEntropy) {
+		threshold := cfg.EntropyThreshold
+		r.detectors = append(r.detectors, detector{
+			name:   "high-entropy-string",
+			secret: true,
+			re:     regexp.MustCompile(`[A-Za-z0-9+/_=-]{20,}`),
+			accept: func(value string) bool {
+				return looksRandom(value) && shannonEntropy(value) >= threshold
+			},
+		})
+	}
+
+	if cfg.DetectorEnabled(config.RedactionDetectorEmail) {
+		r.detectors = append(r.detectors, detector{
+			name: "email",
+			re:   regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+		})
+	}
+
+	return r, nil
+}
+
+// Redact returns text with every detected value replaced by a [REDACTED:<detector>] marker,
+// along with a description of what was masked. Line structure is preserved.
+func (r *Redactor) Redact(text string) (string, []Match) {
+	if r == nil || text == "" {
+		return text, nil
+	}
+
+	var matches []Match
+	var inPrivateKey bool
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if r.privateKey {
+			switch {
+			case privateKeyBegin.MatchString(line):
+				inPrivateKey = !privateKeyEnd.MatchString(line)
+				matches = append(matches, Match{Detector: "private-key", Secret: true, Line: i + 1})
+				line = privateKeyBody.ReplaceAllString(line, mask("private-key"))
+			case inPrivateKey:
+				inPrivateKey = !privateKeyEnd.MatchString(line)
+				line = privateKeyBody.ReplaceAllString(line, mask("private-key"))
+			}
+		}
+
+		for _, d := range r.detectors {
+			var found bool
+			line, found = d.redact(line)
+			if found {
+				matches = append(matches, Match{Detector: d.name, Secret: d.secret, Line: i + 1})
+			}
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n"), matches
+}
+
+func (d detector) redact(line string) (string, bool) {
+	var found bool
+
+	indexes := d.re.FindAllStringSubmatchIndex(line, -1)
+	if len(indexes) == 0 {
+		return line, false
+	}
+
+	var b strings.Builder
+	var last int
+	for _, idx := range indexes {
+		start, end := idx[2*d.group], idx[2*d.group+1]
+		if start < 0 || start < last {
+			continue
+		}
+		if d.accept != nil && !d.accept(line[start:end]) {
+			continue
+		}
+
+		b.WriteString(line[last:start])
+		b.WriteString(mask(d.name))
+		last = end
+		found = true
+	}
+	b.WriteString(line[last:])
+
+	return b.String(), found
+}
+
+func mask(detector string) string {
+	return "[REDACTED:" + detector + "]"
+}
+
+// looksRandom rejects long identifiers and words: random tokens mix digits and letters.
+func looksRandom(value string) bool {
+	var hasDigit, hasLetter bool
+	for _, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			hasLetter = true
+		}
+	}
+
+	return hasDigit && hasLetter
+}
+
+// shannonEntropy returns the entropy of value in bits per character.
+func shannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	var total int
+	for _, c := range value {
+		counts[c]++
+		total++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
@@ -0,0 +1,159 @@
+package review
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// Commands developers can post in discussions after the command prefix, e.g. "/ai explain".
+const (
+	commandReview  = "review"
+	commandExplain = "explain"
+	commandFix     = "fix"
+	commandIgnore  = "ignore"
+	commandStop    = "stop"
+)
+
+// parseCommand returns the lower-cased command of a comment whose first line starts with prefix.
+// The command is empty when the comment holds the prefix alone.
+func parseCommand(text, prefix string) (string, bool) {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], prefix) {
+		return "", false
+	}
+	if len(fields) == 1 {
+		return "", true
+	}
+
+	return strings.ToLower(fields[1]), true
+}
+
+// isCommand reports whether a comment is a command for the bot.
+func (r *replier) isCommand(text string) bool {
+	if !r.config.commands.Enabled {
+		return false
+	}
+
+	_, ok := parseCommand(text, r.config.commands.Prefix)
+	return ok
+}
+
+// handleCommand runs the command of a comment and acknowledges it in the thread.
+func (r *replier) handleCommand(review *upsource.Review, reviewReplier *llm.ReviewReplier, p pendingComment, botUserID string) {
+	name, _ := parseCommand(p.comment.Text, r.config.commands.Prefix)
+	projectID, reviewID := review.GetProjectID(), review.GetReviewID().ReviewID
+	log.Printf("Running command %q from discussion %s (review %s)\n", name, p.discussion.DiscussionID, review.GetBranch())
+
+	var reply string
+	var err error
+	switch name {
+	case commandReview:
+		if err = r.store.SetReviewMuted(projectID, reviewID, false); err == nil {
+			reply = "Reviewing the latest changes now."
+		}
+	case commandExplain:
+		reply, err = reviewReplier.Explain(p.discussion, botUserID)
+	case commandFix:
+		reply, err = reviewReplier.Fix(p.discussion, botUserID)
+	case commandIgnore:
+		reply, err = r.ignoreFinding(review, p, botUserID)
+	case commandStop:
+		if err = r.store.SetReviewMuted(projectID, reviewID, true); err == nil {
+			reply = fmt.Sprintf("OK, I will stay quiet in this review. Post `%s %s` to ask me again.", r.config.commands.Prefix, commandReview)
+		}
+	default:
+		reply = commandHelp(r.config.commands.Prefix)
+	}
+	if err != nil {
+		log.Printf("Command %q failed in discussion %s: %v\n", name, p.discussion.DiscussionID, err)
+		reply = fmt.Sprintf("Sorry, `%s %s` failed. Please try again later.", r.config.commands.Prefix, name)
+	}
+
+	if reply != "" {
+		if err := upsource.AddDiscussionComment(r.ctx, r.upsourceClient, projectID, p.discussion.DiscussionID, p.comment.CommentID, reply); err != nil {
+			log.Printf("Failed to acknowledge command in discussion %s: %v\n", p.discussion.DiscussionID, err)
+		}
+	}
+
+	if name == commandReview && err == nil && r.rereview != nil {
+		if err := r.rereview(review); err != nil {
+			log.Printf("Failed to review %s on request: %v\n", review.GetBranch(), err)
+		}
+	}
+}
+
+// ignoreFinding stores a suppression for the issue raised in the bot comment the command answers,
+// scoped to the anchored file. General discussions are refused: their comments may list several
+// findings, and one command should not suppress an issue across the whole project.
+func (r *replier) ignoreFinding(review *upsource.Review, p pendingComment, botUserID string) (string, error) {
+	path := strings.TrimPrefix(p.discussion.Anchor.FileID, "/")
+	if path == "" {
+		return fmt.Sprintf("`%s %s` only works in discussions on a file. Reply to the comment on the lines you want me to stop reporting.", r.config.commands.Prefix, commandIgnore), nil
+	}
+
+	finding, ok := answeredBotComment(p.discussion.Comments, p.comment, botUserID)
+	if !ok {
+		return "I have not raised an issue in this discussion, so there is nothing to ignore.", nil
+	}
+
+	if _, err := r.store.AddSuppression(store.Suppression{
+		ProjectID: review.GetProjectID(),
+		Path:      path,
+		Text:      normalizeFinding(finding.Text),
+		CreatedBy: p.comment.AuthorID,
+	}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("OK, I will not report this kind of issue in `%s` again.", path), nil
+}
+
+// answeredBotComment returns the bot comment a command answers: its parent when the bot wrote it,
+// otherwise the latest bot comment before the command.
+func answeredBotComment(comments []client.CommentDTO, command client.CommentDTO, botUserID string) (client.CommentDTO, bool) {
+	var latest client.CommentDTO
+	var found bool
+	for _, c := range comments {
+		if c.CommentID == command.CommentID {
+			break
+		}
+		if c.AuthorID != botUserID {
+			continue
+		}
+		if c.CommentID == command.ParentID {
+			return c, true
+		}
+		latest, found = c, true
+	}
+
+	return latest, found
+}
+
+var commandDescriptions = []struct {
+	name        string
+	description string
+}{
+	{commandReview, "review the latest changes now"},
+	{commandExplain, "explain the code or the issue of this discussion"},
+	{commandFix, "propose a patch for the lines of this discussion"},
+	{commandIgnore, "do not report this kind of issue in this file again"},
+	{commandStop, "stay quiet in this review"},
+}
+
+func commandHelp(prefix string) string {
+	var b strings.Builder
+	b.WriteString("Available commands:\n")
+	for _, c := range commandDescriptions {
+		_, _ = fmt.Fprintf(&b, "\n- `%s %s`: %s", prefix, c.name, c.description)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package review
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    string
+		wantCmd bool
+	}{
+		{text: "/ai explain", want: "explain", wantCmd: true},
+		{text: "  /AI Fix please\nthe nil check", want: "fix", wantCmd: true},
+		{text: "/ai", want: "", wantCmd: true},
+		{text: "/aiexplain", wantCmd: false},
+		{text: "please /ai explain", wantCmd: false},
+		{text: "Looks good\n/ai stop", wantCmd: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseCommand(tt.text, "/ai")
+		if ok != tt.wantCmd || got != tt.want {
+			t.Fatalf("parseCommand(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.wantCmd)
+		}
+	}
+}
+
+func TestIgnoreFinding(t *testing.T) {
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	r := &replier{store: s, config: &replierConfig{commands: config.Commands{Enabled: true, Prefix: "/ai"}}}
+
+	command := client.CommentDTO{CommentID: "c4", AuthorID: "dev", Text: "/ai ignore", ParentID: "c3"}
+	discussion := client.DiscussionInFileDTO{
+		Anchor: client.AnchorDTO{FileID: "/a.go"},
+		Comments: []client.CommentDTO{
+			{CommentID: "c1", AuthorID: "dev", Text: "Why is the error dropped here?"},
+			{CommentID: "c2", AuthorID: "bot", Text: "Close errors are not checked."},
+			{CommentID: "c3", AuthorID: "bot", Text: "The **error returned by Close** is ignored."},
+			command,
+		},
+	}
+
+	reply, err := r.ignoreFinding(&upsource.Review{}, pendingComment{discussion: discussion, comment: command}, "bot")
+	if err != nil {
+		t.Fatalf("ignoreFinding: %v", err)
+	}
+	if reply != "OK, I will not report this kind of issue in `a.go` again." {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	sups := s.AllSuppressions()
+	if len(sups) != 1 || sups[0].Path != "a.go" || sups[0].Text != "The error returned by Close is ignored." {
+		t.Fatalf("unexpected suppressions %+v", sups)
+	}
+
+	general := discussion
+	general.Anchor = client.AnchorDTO{}
+	reply, err = r.ignoreFinding(&upsource.Review{}, pendingComment{discussion: general, comment: command}, "bot")
+	if err != nil || reply != "`/ai ignore` only works in discussions on a file. Reply to the comment on the lines you want me to stop reporting." {
+		t.Fatalf("general discussion: reply %q, err %v", reply, err)
+	}
+
+	human := discussion
+	human.Comments = []client.CommentDTO{discussion.Comments[0], command}
+	reply, err = r.ignoreFinding(&upsource.Review{}, pendingComment{discussion: human, comment: command}, "bot")
+	if err != nil || reply != "I have not raised an issue in this discussion, so there is nothing to ignore." {
+		t.Fatalf("human discussion: reply %q, err %v", reply, err)
+	}
+	if len(s.AllSuppressions()) != 1 {
+		t.Fatalf("refused commands must not store suppressions")
+	}
+}
+
+func TestAnsweredBotComment(t *testing.T) {
+	comments := []client.CommentDTO{
+		{CommentID: "c1", AuthorID: "bot", Text: "first"},
+		{CommentID: "c2", AuthorID: "bot", Text: "second"},
+		{CommentID: "c3", AuthorID: "dev", Text: "/ai ignore", ParentID: "c1"},
+		{CommentID: "c4", AuthorID: "bot", Text: "later"},
+	}
+
+	if got, ok := answeredBotComment(comments, comments[2], "bot"); !ok || got.CommentID != "c1" {
+		t.Fatalf("answeredBotComment with bot parent = %q, %v, want c1", got.CommentID, ok)
+	}
+
+	command := client.CommentDTO{CommentID: "c3", AuthorID: "dev", Text: "/ai ignore"}
+	if got, ok := answeredBotComment(comments, command, "bot"); !ok || got.CommentID != "c2" {
+		t.Fatalf("answeredBotComment without parent = %q, %v, want c2", got.CommentID, ok)
+	}
+}
@@ -0,0 +1,187 @@
+package review
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// existingDiscussion is a discussion already present in a review, reduced to what duplicate detection needs.
+type existingDiscussion struct {
+	id            string
+	file          string // Empty for general discussions.
+	line          int    // 0 when unknown.
+	text          string // The first comment, which states the issue.
+	lastCommentID string
+	lastAuthorID  string
+}
+
+var (
+	fencedCodeBlock = regexp.MustCompile("(?s)```.*?```")
+	// aggregatedCommentPrefix matches the "**LOW** path:line " prefix of entries in the low-priority discussion.
+	aggregatedCommentPrefix = regexp.MustCompile(`(?m)^\*\*[A-Z]+\*\* \S*:\d+ `)
+)
+
+// deduplicateComments drops comments already raised in the review by the bot or by humans.
+// In reply mode a duplicate of an inline thread is added to that thread instead,
+// unless the bot already has the last word there.
+// When existing discussions cannot be loaded all comments are kept.
+func (r *Reviewer) deduplicateComments(review *upsource.Review, comments []*llm.ReviewComment) []*llm.ReviewComment {
+	cfg := r.config.Review.Dedup
+	if !cfg.Enabled || len(comments) == 0 {
+		return comments
+	}
+
+	discussions, err := r.existingDiscussions(review)
+	if err != nil {
+		log.Printf("Skipping duplicate check for %s: %v\n", review.GetBranch(), err)
+		return comments
+	}
+
+	var botUserID string
+	if cfg.Mode == config.DedupModeReply {
+		if botUserID, err = r.replier.resolveBotUserID(); err != nil {
+			log.Printf("Failed to resolve bot user id, duplicates will be suppressed: %v\n", err)
+		}
+	}
+
+	kept := make([]*llm.ReviewComment, 0, len(comments))
+	for _, comment := range comments {
+		duplicate := findDuplicate(comment, discussions, cfg)
+		if duplicate == nil {
+			kept = append(kept, comment)
+			continue
+		}
+
+		log.Printf("Comment on %s:%d duplicates discussion %s: %s\n", comment.FilePath, comment.LineNumber, duplicate.id, comment.Comment)
+
+		if cfg.Mode != config.DedupModeReply || botUserID == "" || duplicate.file == "" || duplicate.lastAuthorID == botUserID {
+			continue
+		}
+
+		text := fmt.Sprintf("This still applies after the latest changes (AI generated):\n\n%s", commentBody(comment))
+		if err := upsource.AddDiscussionComment(r.ctx, r.upsourceClient, review.GetProjectID(), duplicate.id, duplicate.lastCommentID, text); err != nil {
+			log.Printf("Failed to reply to discussion %s: %v\n", duplicate.id, err)
+			continue
+		}
+		duplicate.lastAuthorID = botUserID
+	}
+
+	return kept
+}
+
+func (r *Reviewer) existingDiscussions(review *upsource.Review) ([]*existingDiscussion, error) {
+	discussions, err := upsource.ListReviewDiscussions(r.ctx, r.upsourceClient, review)
+	if err != nil {
+		return nil, fmt.Errorf("list discussions: %w", err)
+	}
+
+	locations := upsource.ResolveDiscussionLocations(r.ctx, r.upsourceClient, review, discussions)
+
+	existing := make([]*existingDiscussion, 0, len(discussions))
+	for _, d := range discussions {
+		if len(d.Comments) == 0 {
+			continue
+		}
+
+		last := d.Comments[len(d.Comments)-1]
+		existing = append(existing, &existingDiscussion{
+			id:            d.DiscussionID,
+			file:          locations[d.DiscussionID].File,
+			line:          locations[d.DiscussionID].Line,
+			text:          d.Comments[0].Text,
+			lastCommentID: last.CommentID,
+			lastAuthorID:  last.AuthorID,
+		})
+	}
+
+	return existing, nil
+}
+
+// findDuplicate returns the discussion that already raises the comment's issue, or nil.
+// Inline comments are compared with discussions on the same file within the line window,
+// other comments with general discussions and discussions on the same file.
+func findDuplicate(comment *llm.ReviewComment, discussions []*existingDiscussion, cfg config.Dedup) *existingDiscussion {
+	inline := comment.LineNumber > 0 && comment.FilePath != "" && comment.LineVerified
+
+	for _, d := range discussions {
+		switch {
+		case d.file == "":
+			if inline {
+				continue
+			}
+		case d.file != comment.FilePath:
+			continue
+		case inline && d.line > 0 && abs(d.line-comment.LineNumber) > cfg.LineWindow:
+			continue
+		}
+
+		for _, paragraph := range discussionParagraphs(d.text) {
+			if textSimilarity(comment.Comment, paragraph) >= cfg.MinSimilarity {
+				return d
+			}
+		}
+	}
+
+	return nil
+}
+
+// discussionParagraphs splits a discussion comment into the issues it raises: the low-priority
+// discussion lists one issue per paragraph. Code blocks and entry prefixes are removed.
+func discussionParagraphs(text string) []string {
+	text = fencedCodeBlock.ReplaceAllString(text, "")
+	text = aggregatedCommentPrefix.ReplaceAllString(text, "")
+
+	var paragraphs []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if strings.TrimSpace(paragraph) != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+
+	return paragraphs
+}
+
+// textSimilarity returns the share of common words of two texts (Jaccard index), ignoring case and words shorter than 3 letters.
+func textSimilarity(a, b string) float64 {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	var common int
+	for word := range wordsA {
+		if wordsB[word] {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(wordsA)+len(wordsB)-common)
+}
+
+func words(text string) map[string]bool {
+	result := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		if len([]rune(word)) >= 3 {
+			result[word] = true
+		}
+	}
+
+	return result
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
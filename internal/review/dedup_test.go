@@ -0,0 +1,58 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+func TestFindDuplicate(t *testing.T) {
+	cfg := config.Dedup{Enabled: true, Mode: config.DedupModeSuppress, LineWindow: 3, MinSimilarity: 0.6}
+
+	discussions := []*existingDiscussion{
+		{id: "inline", file: "pkg/a.go", line: 10, text: "The error returned by `Close` is ignored, handle it.\n\n```diff\n-f.Close()\n+if err := f.Close(); err != nil {\n```"},
+		{id: "aggregate", text: "### Low-Medium Priority Comments (AI generated):\n\n**LOW** pkg/b.go:0 Consider renaming `tmp` to something descriptive.\n\n**MEDIUM** pkg/c.go:0 The loop allocates a new buffer on every iteration.\n\n"},
+	}
+
+	tests := []struct {
+		name    string
+		comment *llm.ReviewComment
+		want    string
+	}{
+		{
+			name:    "same issue near the same line",
+			comment: &llm.ReviewComment{FilePath: "pkg/a.go", LineNumber: 12, LineVerified: true, Comment: "The error returned by Close is ignored; handle it."},
+			want:    "inline",
+		},
+		{
+			name:    "same issue too far away",
+			comment: &llm.ReviewComment{FilePath: "pkg/a.go", LineNumber: 20, LineVerified: true, Comment: "The error returned by Close is ignored; handle it."},
+		},
+		{
+			name:    "different issue at the same line",
+			comment: &llm.ReviewComment{FilePath: "pkg/a.go", LineNumber: 10, LineVerified: true, Comment: "This function is too long, split it."},
+		},
+		{
+			name:    "same issue in the aggregated discussion",
+			comment: &llm.ReviewComment{FilePath: "pkg/c.go", Comment: "The loop allocates a new buffer on each iteration."},
+			want:    "aggregate",
+		},
+		{
+			name:    "inline comments are not compared with general discussions",
+			comment: &llm.ReviewComment{FilePath: "pkg/c.go", LineNumber: 3, LineVerified: true, Comment: "The loop allocates a new buffer on each iteration."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if d := findDuplicate(tt.comment, discussions, cfg); d != nil {
+				got = d.id
+			}
+			if got != tt.want {
+				t.Fatalf("expected duplicate %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
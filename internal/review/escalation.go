@@ -0,0 +1,134 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// escalate hands a discussion over to human reviewers: it adds them to the review, posts a neutral
+// summary of both positions for them and labels the discussion, so the bot does not reply in it any more.
+func (r *replier) escalate(review *upsource.Review, reviewReplier *llm.ReviewReplier, d client.DiscussionInFileDTO, parentCommentID, botUserID string) error {
+	reviewers := escalationReviewers(r.fileOwner(review, d), r.config.escalation.ReviewersFor(review.GetProjectID()), review.GetAuthorID(), botUserID)
+	if len(reviewers) == 0 {
+		return errors.New("no reviewer to escalate to")
+	}
+
+	summary, err := reviewReplier.SummarizeDisagreement(d, botUserID)
+	if err != nil {
+		return fmt.Errorf("summarise disagreement: %w", err)
+	}
+
+	for _, userID := range reviewers {
+		if err := upsource.AddReviewer(r.ctx, r.upsourceClient, review, userID); err != nil {
+			return fmt.Errorf("add reviewer %s: %w", userID, err)
+		}
+	}
+
+	if err := upsource.AddDiscussionComment(r.ctx, r.upsourceClient, review.GetProjectID(), d.DiscussionID, parentCommentID, escalationComment(r.displayNames(reviewers), summary)); err != nil {
+		return fmt.Errorf("post summary: %w", err)
+	}
+
+	// The label is added last: a discussion whose summary could not be posted is escalated again on the next poll,
+	// one whose label could not be added is labelled by isEscalated.
+	if err := upsource.AddDiscussionLabel(r.ctx, r.upsourceClient, review.GetProjectID(), d.DiscussionID, r.config.escalation.Label); err != nil {
+		return fmt.Errorf("label discussion: %w", err)
+	}
+
+	log.Printf("Escalated discussion %s (review %s) to %s\n", d.DiscussionID, review.GetBranch(), strings.Join(reviewers, ", "))
+
+	return nil
+}
+
+// isEscalated reports whether a discussion was handed over to human reviewers. A discussion that has
+// the bot's escalation note but not the label (adding it failed) is labelled now.
+func (r *replier) isEscalated(review *upsource.Review, d client.DiscussionInFileDTO, botUserID string) bool {
+	label := r.config.escalation.Label
+	if upsource.HasDiscussionLabel(d, label) {
+		return true
+	}
+	if !upsource.HasEscalationNote(d, botUserID) {
+		return false
+	}
+
+	if err := upsource.AddDiscussionLabel(r.ctx, r.upsourceClient, review.GetProjectID(), d.DiscussionID, label); err != nil {
+		log.Printf("Failed to label escalated discussion %s: %v\n", d.DiscussionID, err)
+	}
+
+	return true
+}
+
+// fileOwner returns the Upsource owner of the file a discussion is anchored to, or "" when
+// code owners are not asked or the file has no owner.
+func (r *replier) fileOwner(review *upsource.Review, d client.DiscussionInFileDTO) string {
+	if !r.config.escalation.CodeOwners || d.Anchor.FileID == "" {
+		return ""
+	}
+
+	owners, err := upsource.GetFileOwners(r.ctx, r.upsourceClient, review)
+	if err != nil {
+		log.Printf("Failed to get file owners of review %s: %v\n", review.GetBranch(), err)
+		return ""
+	}
+
+	return owners[strings.TrimPrefix(d.Anchor.FileID, "/")]
+}
+
+// displayNames returns the names of the users, or their IDs when the names cannot be resolved.
+func (r *replier) displayNames(userIDs []string) []string {
+	var names map[string]string
+	if r.users != nil {
+		names = r.users.UserNames(userIDs)
+	}
+
+	out := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if name := names[id]; name != "" {
+			out = append(out, name)
+		} else {
+			out = append(out, id)
+		}
+	}
+
+	return out
+}
+
+// escalationReviewers returns the owner of the discussed file when there is one, and the designated
+// reviewers of the project otherwise. The author of the review and the bot are never asked.
+func escalationReviewers(owner string, designated []string, authorID, botUserID string) []string {
+	eligible := func(userIDs []string) []string {
+		var out []string
+		for _, id := range userIDs {
+			if id != "" && id != authorID && id != botUserID {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+
+	if reviewers := eligible([]string{owner}); len(reviewers) > 0 {
+		return reviewers
+	}
+
+	return eligible(designated)
+}
+
+// escalationComment is the note that hands a discussion over to the named reviewers.
+func escalationComment(names []string, summary string) string {
+	return fmt.Sprintf("%s%s to decide.\n\n**Summary of the discussion**\n\n%s", upsource.EscalationNotePrefix, joinNames(names), summary)
+}
+
+// joinNames joins names as "A", "A and B" or "A, B and C".
+func joinNames(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
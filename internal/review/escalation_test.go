@@ -0,0 +1,64 @@
+package review
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEscalationReviewers(t *testing.T) {
+	tests := []struct {
+		name       string
+		owner      string
+		designated []string
+		want       []string
+	}{
+		{name: "file owner first", owner: "owner", designated: []string{"lead"}, want: []string{"owner"}},
+		{name: "designated without owner", designated: []string{"lead", "architect"}, want: []string{"lead", "architect"}},
+		{name: "author owns the file", owner: "author", designated: []string{"lead"}, want: []string{"lead"}},
+		{name: "author and bot are skipped", designated: []string{"author", "bot", "lead"}, want: []string{"lead"}},
+		{name: "nobody to ask", owner: "bot", designated: []string{"author"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := escalationReviewers(tt.owner, tt.designated, "author", "bot")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("escalationReviewers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscalationComment(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  string
+	}{
+		{names: []string{"Alice"}, want: "We could not agree here, so I asked Alice to decide.\n\n**Summary of the discussion**\n\nBoth sides."},
+		{names: []string{"Alice", "Bob"}, want: "We could not agree here, so I asked Alice and Bob to decide.\n\n**Summary of the discussion**\n\nBoth sides."},
+		{names: []string{"Alice", "Bob", "Carol"}, want: "We could not agree here, so I asked Alice, Bob and Carol to decide.\n\n**Summary of the discussion**\n\nBoth sides."},
+	}
+
+	for _, tt := range tests {
+		if got := escalationComment(tt.names, "Both sides."); got != tt.want {
+			t.Fatalf("escalationComment(%v) = %q, want %q", tt.names, got, tt.want)
+		}
+	}
+}
+
+type staticUserNames map[string]string
+
+func (n staticUserNames) UserNames(userIDs []string) map[string]string {
+	return n
+}
+
+func TestDisplayNames(t *testing.T) {
+	r := &replier{users: staticUserNames{"u1": "Alice"}}
+	if got := r.displayNames([]string{"u1", "u2"}); !reflect.DeepEqual(got, []string{"Alice", "u2"}) {
+		t.Fatalf("displayNames = %v, want [Alice u2]", got)
+	}
+
+	if got := (&replier{}).displayNames([]string{"u1"}); !reflect.DeepEqual(got, []string{"u1"}) {
+		t.Fatalf("displayNames without resolver = %v, want [u1]", got)
+	}
+}
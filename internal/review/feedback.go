@@ -0,0 +1,218 @@
+package review
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// recordFeedback stores a posted inline comment so the response of the developers can be classified later.
+func (r *Reviewer) recordFeedback(review *upsource.Review, discussionID string, comment *llm.ReviewComment) {
+	if !r.config.Feedback.Enabled || discussionID == "" {
+		return
+	}
+
+	err := r.store.AddFeedback(store.Feedback{
+		DiscussionID:  discussionID,
+		ProjectID:     review.GetProjectID(),
+		ReviewID:      review.GetReviewID().ReviewID,
+		FileType:      fileType(comment.FilePath),
+		Severity:      comment.Severity,
+		Model:         r.config.Providers.ActiveModel(),
+		PromptVersion: promptVersion(r.config.Review),
+	})
+	if err != nil {
+		log.Printf("Failed to record comment in discussion %s for feedback: %v\n", discussionID, err)
+	}
+}
+
+// collectFeedback classifies the responses to the bot's comments in already-reviewed reviews
+// once their discussions are settled, and updates the acceptance rate metrics.
+// Errors are logged per review; a single failure never aborts the loop.
+func (r *Reviewer) collectFeedback() error {
+	botUserID, err := r.replier.resolveBotUserID()
+	if err != nil {
+		return fmt.Errorf("failed to resolve bot user id: %w", err)
+	}
+
+	reviews, err := upsource.ListReviewedReviews(r.ctx, r.upsourceClient, r.config.Upsource.Query, r.config.Upsource.ReviewedLabel)
+	if err != nil {
+		return fmt.Errorf("failed to list reviewed reviews: %w", err)
+	}
+
+	settleAfter := time.Duration(r.config.Feedback.SettleAfterDays) * 24 * time.Hour
+	for _, review := range reviews {
+		pending := r.store.PendingFeedback(review.GetProjectID(), review.GetReviewID().ReviewID)
+		if len(pending) == 0 {
+			continue
+		}
+		if err := r.classifyFeedback(review, pending, botUserID, settleAfter); err != nil {
+			log.Printf("Feedback pass error in review %s: %v\n", review.GetBranch(), err)
+		}
+	}
+
+	for _, summary := range store.Summarize(r.store.AllFeedback(), func(f store.Feedback) string { return f.Model }) {
+		if summary.Classified() > 0 {
+			metrics.DefaultRecorder.SetAcceptanceRate(summary.Key, summary.AcceptanceRate())
+		}
+	}
+
+	return nil
+}
+
+func (r *Reviewer) classifyFeedback(review *upsource.Review, pending []store.Feedback, botUserID string, settleAfter time.Duration) error {
+	discussions, err := upsource.ListReviewDiscussions(r.ctx, r.upsourceClient, review)
+	if err != nil {
+		return fmt.Errorf("list discussions: %w", err)
+	}
+
+	byID := make(map[string]client.DiscussionInFileDTO, len(discussions))
+	for _, d := range discussions {
+		byID[d.DiscussionID] = d
+	}
+
+	reviewReplier := r.replier.llmReplier.ForReview(review)
+	now := time.Now()
+
+	for _, f := range pending {
+		d, ok := byID[f.DiscussionID]
+		if !ok {
+			continue
+		}
+
+		outcome, needsLLM := settledOutcome(d, botUserID, now, settleAfter)
+		if needsLLM {
+			if outcome, err = reviewReplier.ClassifyFeedback(d, botUserID); err != nil {
+				log.Printf("Failed to classify feedback in discussion %s: %v\n", d.DiscussionID, err)
+				continue
+			}
+		}
+		if outcome == store.OutcomePending {
+			continue
+		}
+
+		if err := r.store.SetOutcome(f.DiscussionID, outcome); err != nil {
+			log.Printf("Failed to store feedback of discussion %s: %v\n", d.DiscussionID, err)
+			continue
+		}
+		metrics.DefaultRecorder.RecordCommentOutcome(f.Model, f.Severity, f.FileType, string(outcome))
+		log.Printf("Discussion %s (review %s): comment %s\n", d.DiscussionID, review.GetBranch(), outcome)
+
+		if outcome == store.OutcomeDisagreed && r.config.Suppressions.Learn {
+			if err := learnRejection(r.store, review.GetProjectID(), d); err != nil {
+				log.Printf("Failed to store rejected finding of discussion %s: %v\n", d.DiscussionID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// settledOutcome classifies a bot discussion that is resolved or has been quiet for settleAfter.
+// Discussions without replies are classified by the reactions to the bot's comment and by whether
+// they are resolved. needsLLM is true when developers replied, so the transcript must be classified.
+// The outcome is pending while the discussion is not settled.
+func settledOutcome(d client.DiscussionInFileDTO, botUserID string, now time.Time, settleAfter time.Duration) (outcome store.Outcome, needsLLM bool) {
+	if len(d.Comments) == 0 {
+		return store.OutcomePending, false
+	}
+
+	resolved := d.IsResolved != nil && *d.IsResolved
+	var lastActivity int64
+	var replied bool
+	for _, c := range d.Comments {
+		lastActivity = max(lastActivity, c.Date)
+		if c.AuthorID != botUserID {
+			replied = true
+		}
+	}
+	if !resolved && now.Sub(time.UnixMilli(lastActivity)) < settleAfter {
+		return store.OutcomePending, false
+	}
+
+	if replied {
+		return store.OutcomePending, true
+	}
+
+	switch reactionOutcome(d.Comments[0], botUserID) {
+	case store.OutcomeAccepted:
+		return store.OutcomeAccepted, false
+	case store.OutcomeDisagreed:
+		return store.OutcomeDisagreed, false
+	}
+	if resolved {
+		return store.OutcomeResolvedWithoutReply, false
+	}
+
+	return store.OutcomeIgnored, false
+}
+
+// reactionOutcome reads a thumbs up or down from developers' reactions to a comment.
+// It returns OutcomePending when there is no such reaction or both kinds are present.
+func reactionOutcome(comment client.CommentDTO, botUserID string) store.Outcome {
+	var up, down bool
+	for _, reaction := range comment.Reactions {
+		if !reactedByHuman(reaction, botUserID) {
+			continue
+		}
+
+		id := strings.ToLower(reaction.ID)
+		switch {
+		case strings.Contains(id, "down") || strings.Contains(id, "dislike") || id == "-1":
+			down = true
+		case strings.Contains(id, "up") || strings.Contains(id, "like") || id == "+1":
+			up = true
+		}
+	}
+
+	switch {
+	case up && !down:
+		return store.OutcomeAccepted
+	case down && !up:
+		return store.OutcomeDisagreed
+	default:
+		return store.OutcomePending
+	}
+}
+
+func reactedByHuman(reaction client.ReactionDTO, botUserID string) bool {
+	for _, userID := range reaction.UsersIDs {
+		if userID != botUserID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fileType returns the extension of a file without the dot, or its name when it has none, e.g. "Dockerfile".
+func fileType(filePath string) string {
+	if filePath == "" {
+		return "none"
+	}
+
+	name := path.Base(filePath)
+	if ext := path.Ext(name); ext != "" && ext != name {
+		return strings.TrimPrefix(ext, ".")
+	}
+
+	return name
+}
+
+// promptVersion identifies the review prompt templates, so outcomes of different prompts can be compared.
+func promptVersion(review config.Review) string {
+	sum := sha256.Sum256([]byte(review.SystemMessageTemplate() + "\x00" + review.UserPromptTemplate))
+	return hex.EncodeToString(sum[:4])
+}
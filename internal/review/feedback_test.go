@@ -0,0 +1,103 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+)
+
+func TestSettledOutcome(t *testing.T) {
+	const botID = "bot-1"
+	resolved := true
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	settleAfter := 7 * 24 * time.Hour
+	old := now.Add(-8 * 24 * time.Hour).UnixMilli()
+	recent := now.Add(-time.Hour).UnixMilli()
+
+	tests := []struct {
+		name         string
+		disc         client.DiscussionInFileDTO
+		wantOutcome  store.Outcome
+		wantNeedsLLM bool
+	}{
+		{
+			name: "recent and unresolved — pending",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{AuthorID: botID, Date: recent},
+			}},
+			wantOutcome: store.OutcomePending,
+		},
+		{
+			name: "quiet without reply — ignored",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{AuthorID: botID, Date: old},
+			}},
+			wantOutcome: store.OutcomeIgnored,
+		},
+		{
+			name: "resolved without reply",
+			disc: client.DiscussionInFileDTO{IsResolved: &resolved, Comments: []client.CommentDTO{
+				{AuthorID: botID, Date: recent},
+			}},
+			wantOutcome: store.OutcomeResolvedWithoutReply,
+		},
+		{
+			name: "thumbs up — accepted",
+			disc: client.DiscussionInFileDTO{IsResolved: &resolved, Comments: []client.CommentDTO{
+				{AuthorID: botID, Date: recent, Reactions: []client.ReactionDTO{{ID: "thumbs-up", UsersIDs: []string{"dev"}}}},
+			}},
+			wantOutcome: store.OutcomeAccepted,
+		},
+		{
+			name: "thumbs down — disagreed",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{AuthorID: botID, Date: old, Reactions: []client.ReactionDTO{{ID: "thumbs-down", UsersIDs: []string{"dev"}}}},
+			}},
+			wantOutcome: store.OutcomeDisagreed,
+		},
+		{
+			name: "bot's own reaction is ignored",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{AuthorID: botID, Date: old, Reactions: []client.ReactionDTO{{ID: "thumbs-up", UsersIDs: []string{botID}}}},
+			}},
+			wantOutcome: store.OutcomeIgnored,
+		},
+		{
+			name: "resolved after reply — classified by the LLM",
+			disc: client.DiscussionInFileDTO{IsResolved: &resolved, Comments: []client.CommentDTO{
+				{AuthorID: botID, Date: recent},
+				{AuthorID: "dev", Date: recent, Text: "Fixed"},
+			}},
+			wantOutcome:  store.OutcomePending,
+			wantNeedsLLM: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, needsLLM := settledOutcome(tt.disc, botID, now, settleAfter)
+			if outcome != tt.wantOutcome || needsLLM != tt.wantNeedsLLM {
+				t.Fatalf("settledOutcome = (%q, %v), want (%q, %v)", outcome, needsLLM, tt.wantOutcome, tt.wantNeedsLLM)
+			}
+		})
+	}
+}
+
+func TestFileType(t *testing.T) {
+	tests := map[string]string{
+		"internal/review/feedback.go": "go",
+		"migrations/001.up.sql":       "sql",
+		"deploy/Dockerfile":           "Dockerfile",
+		".gitignore":                  ".gitignore",
+		"":                            "none",
+	}
+
+	for filePath, want := range tests {
+		if got := fileType(filePath); got != want {
+			t.Fatalf("fileType(%q) = %q, want %q", filePath, got, want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package review
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// pendingComment is a mention or command comment waiting for the bot's answer.
+type pendingComment struct {
+	discussion client.DiscussionInFileDTO
+	comment    client.CommentDTO
+}
+
+// scanOpenReviews handles commands and answers mentions of the bot in the discussions of any open
+// review matching the search query. Errors are logged per review.
+func (r *replier) scanOpenReviews(botUserID string) error {
+	reviews, err := upsource.ListOpenReviews(r.ctx, r.upsourceClient, r.config.searchReviewsQuery)
+	if err != nil {
+		return fmt.Errorf("failed to list open reviews: %w", err)
+	}
+
+	log.Printf("Mention and command pass: scanning %d open reviews\n", len(reviews))
+
+	open := make(map[client.ReviewIdDTO]bool, len(reviews))
+	for _, review := range reviews {
+		open[review.GetReviewID()] = true
+	}
+	if err := r.store.PruneHandledReviews(func(projectID, reviewID string) bool {
+		return open[client.ReviewIdDTO{ProjectID: projectID, ReviewID: reviewID}]
+	}); err != nil {
+		log.Printf("Failed to forget the handled comments of closed reviews: %v\n", err)
+	}
+
+	for _, review := range reviews {
+		if err := r.scanOpenReview(review, botUserID); err != nil {
+			log.Printf("Mention and command pass error in review %s: %v\n", review.GetBranch(), err)
+		}
+	}
+
+	return nil
+}
+
+func (r *replier) scanOpenReview(review *upsource.Review, botUserID string) error {
+	discussions, err := upsource.ListReviewDiscussions(r.ctx, r.upsourceClient, review)
+	if err != nil {
+		return fmt.Errorf("list discussions: %w", err)
+	}
+
+	projectID, reviewID := review.GetProjectID(), review.GetReviewID().ReviewID
+	handled := func(c client.CommentDTO) bool {
+		return r.store.IsCommentHandled(projectID, reviewID, c.CommentID)
+	}
+
+	var commentIDs []string
+	var commands, mentions []pendingComment
+	for _, d := range discussions {
+		for _, c := range d.Comments {
+			commentIDs = append(commentIDs, c.CommentID)
+		}
+
+		if r.config.commands.Enabled {
+			if c, ok := upsource.LatestUnansweredComment(d, botUserID, r.isCommand); ok {
+				if !handled(c) {
+					commands = append(commands, pendingComment{discussion: d, comment: c})
+				}
+				continue
+			}
+		}
+
+		if r.config.mentions.Enabled {
+			if c, ok := upsource.ShouldAnswerMention(d, botUserID, r.isMention, r.config.maxPerThread); ok && !handled(c) {
+				mentions = append(mentions, pendingComment{discussion: d, comment: c})
+			}
+		}
+	}
+	if err := r.store.PruneHandledComments(projectID, reviewID, commentIDs); err != nil {
+		log.Printf("Failed to forget the deleted comments of review %s: %v\n", review.GetBranch(), err)
+	}
+	if len(commands) == 0 && (len(mentions) == 0 || r.store.IsReviewMuted(projectID, reviewID)) {
+		return nil
+	}
+
+	// Only reviews with comments for the bot are loaded with their repository and changes.
+	review, err = upsource.LoadReview(r.ctx, r.upsourceClient, review)
+	if err != nil {
+		return fmt.Errorf("load review: %w", err)
+	}
+	reviewReplier := r.llmReplier.ForReview(review)
+
+	// A comment counts as handled even when the LLM fails or stays silent, so it is not retried on every poll.
+	for _, p := range commands {
+		r.markHandled(review, p.comment)
+		r.handleCommand(review, reviewReplier, p, botUserID)
+	}
+
+	// Mentions in a muted review wait until it is unmuted; a command above may have unmuted it.
+	if r.store.IsReviewMuted(projectID, reviewID) {
+		return nil
+	}
+	for _, p := range mentions {
+		r.markHandled(review, p.comment)
+		r.answerMention(review, reviewReplier, p, botUserID)
+	}
+
+	return nil
+}
+
+// markHandled records a mention or command comment as handled, so it is not handled again, also after a restart.
+func (r *replier) markHandled(review *upsource.Review, c client.CommentDTO) {
+	if err := r.store.MarkCommentHandled(review.GetProjectID(), review.GetReviewID().ReviewID, c.CommentID); err != nil {
+		log.Printf("Failed to record comment %s as handled: %v\n", c.CommentID, err)
+	}
+}
+
+func (r *replier) answerMention(review *upsource.Review, reviewReplier *llm.ReviewReplier, p pendingComment, botUserID string) {
+	reply, err := reviewReplier.AnswerMention(p.discussion, botUserID)
+	if err != nil {
+		log.Printf("Failed to answer mention in discussion %s: %v\n", p.discussion.DiscussionID, err)
+		return
+	}
+	if reply.Comment == "" {
+		return
+	}
+
+	if err := upsource.AddDiscussionComment(r.ctx, r.upsourceClient, review.GetProjectID(), p.discussion.DiscussionID, p.comment.CommentID, reply.Comment); err != nil {
+		log.Printf("Failed to post answer in discussion %s: %v\n", p.discussion.DiscussionID, err)
+		return
+	}
+	metrics.DefaultRecorder.RecordReplySent()
+	log.Printf("Answered mention in discussion %s (review %s)\n", p.discussion.DiscussionID, review.GetBranch())
+}
+
+// isMention reports whether a comment mentions the bot's login or contains one of the trigger words.
+func (r *replier) isMention(text string) bool {
+	if r.mentionPattern == nil {
+		r.mentionPattern = mentionPattern(r.botLogin, r.config.mentions.TriggerWords)
+	}
+
+	return r.mentionPattern.MatchString(text)
+}
+
+// mentionPattern matches "@login" and the trigger words as whole words, ignoring case.
+// Hyphens count as word characters, so "@ai" does not match "@ai-bot".
+func mentionPattern(login string, triggerWords []string) *regexp.Regexp {
+	var alternatives []string
+	if login != "" {
+		alternatives = append(alternatives, regexp.QuoteMeta("@"+login))
+	}
+	for _, word := range triggerWords {
+		alternatives = append(alternatives, regexp.QuoteMeta(strings.TrimSpace(word)))
+	}
+	if len(alternatives) == 0 {
+		return regexp.MustCompile(`$^`) // Matches nothing.
+	}
+
+	return regexp.MustCompile(`(?i)(?:^|[^\w@-])(?:` + strings.Join(alternatives, "|") + `)(?:$|[^\w-])`)
+}
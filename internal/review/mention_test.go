@@ -0,0 +1,85 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+func TestMentionPattern(t *testing.T) {
+	pattern := mentionPattern("ai-bot", []string{"@ai", "hey reviewer"})
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "@ai-bot is this safe?", want: true},
+		{text: "What do you think, @AI-Bot?", want: true},
+		{text: "@ai please check", want: true},
+		{text: "Hey reviewer, thoughts?", want: true},
+		{text: "ping @ai-bots", want: false},
+		{text: "mail me at me@ai.example", want: false},
+		{text: "Looks good", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := pattern.MatchString(tt.text); got != tt.want {
+			t.Fatalf("mentionPattern.MatchString(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+
+	if mentionPattern("", nil).MatchString("@anyone") {
+		t.Fatalf("empty mention pattern should match nothing")
+	}
+}
+
+func TestScanOpenReviewLeavesMentionsOfMutedReviews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/~rpc/getProjectDiscussions" {
+			t.Errorf("unexpected request %s: a muted review must not be loaded", req.URL.Path)
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": client.DiscussionsInProjectDTO{
+			Discussions: []client.DiscussionInFileDTO{{
+				DiscussionID: "d1",
+				Review:       &client.ShortReviewInfoDTO{ReviewID: client.ReviewIdDTO{ProjectID: "p", ReviewID: "r1"}},
+				Comments:     []client.CommentDTO{{CommentID: "c1", AuthorID: "dev", Text: "@ai-bot is this safe?"}},
+			}},
+		}})
+	}))
+	defer server.Close()
+
+	upsourceClient, err := client.New(client.Options{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	if err := s.SetReviewMuted("p", "r1", true); err != nil {
+		t.Fatalf("SetReviewMuted: %v", err)
+	}
+
+	r := &replier{ctx: context.Background(), upsourceClient: upsourceClient, store: s, botLogin: "ai-bot", config: &replierConfig{
+		mentions: config.Mentions{Enabled: true},
+	}}
+	review := upsource.NewReview(&client.ReviewDescriptorDTO{ReviewID: client.ReviewIdDTO{ProjectID: "p", ReviewID: "r1"}})
+
+	if err := r.scanOpenReview(review, "bot"); err != nil {
+		t.Fatalf("scanOpenReview: %v", err)
+	}
+	if s.IsCommentHandled("p", "r1", "c1") {
+		t.Fatal("a mention in a muted review must be answered once the review is unmuted")
+	}
+}
@@ -0,0 +1,92 @@
+package review
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// updateParticipantState makes the bot a reviewer of the review and accepts or rejects it based on the findings of the review.
+func (r *Reviewer) updateParticipantState(review *upsource.Review, comments []*llm.ReviewComment) {
+	botUserID, err := r.replier.resolveBotUserID()
+	if err != nil {
+		log.Printf("Failed to resolve bot user id, participant state of %s is not updated: %v\n", review.GetBranch(), err)
+		return
+	}
+
+	state := reviewerState(comments, r.config.Review.Posting, r.config.Review.Participant.RejectMinSeverity)
+	if err := upsource.SetReviewerState(r.ctx, r.upsourceClient, review, botUserID, state); err != nil {
+		log.Printf("Failed to set participant state of %s: %v\n", review.GetBranch(), err)
+	}
+}
+
+// reviewerState rejects a review when a finding is at least rejectMinSeverity, and accepts it otherwise.
+// Comments below the posting thresholds are ignored.
+func reviewerState(comments []*llm.ReviewComment, posting config.Posting, rejectMinSeverity string) client.ParticipantStateEnum {
+	for _, comment := range comments {
+		if postingTargetFor(comment, posting) == postingTargetLog {
+			continue
+		}
+		if llm.SeverityRank(comment.Severity) <= llm.SeverityRank(rejectMinSeverity) {
+			return client.ParticipantStateEnumRejected
+		}
+	}
+
+	return client.ParticipantStateEnumAccepted
+}
+
+// acceptResolvedReviews accepts the reviews the bot rejected once all discussions it started are resolved.
+// Errors are logged per review; a single failure never aborts the loop.
+func (r *Reviewer) acceptResolvedReviews() error {
+	botUserID, err := r.replier.resolveBotUserID()
+	if err != nil {
+		return fmt.Errorf("failed to resolve bot user id: %w", err)
+	}
+
+	reviews, err := upsource.ListReviewedReviews(r.ctx, r.upsourceClient, r.config.Upsource.Query, r.config.Upsource.ReviewedLabel)
+	if err != nil {
+		return fmt.Errorf("failed to list reviewed reviews: %w", err)
+	}
+
+	for _, review := range reviews {
+		if state, ok := review.GetParticipantState(botUserID); !ok || state != client.ParticipantStateEnumRejected {
+			continue
+		}
+
+		discussions, err := upsource.ListReviewDiscussions(r.ctx, r.upsourceClient, review)
+		if err != nil {
+			log.Printf("Failed to list discussions of %s: %v\n", review.GetBranch(), err)
+			continue
+		}
+		if !allBotDiscussionsResolved(discussions, botUserID) {
+			continue
+		}
+
+		if err := upsource.SetReviewerState(r.ctx, r.upsourceClient, review, botUserID, client.ParticipantStateEnumAccepted); err != nil {
+			log.Printf("Failed to accept review %s: %v\n", review.GetBranch(), err)
+			continue
+		}
+		log.Printf("Accepted review %s: all discussions started by the bot are resolved.\n", review.GetBranch())
+	}
+
+	return nil
+}
+
+// allBotDiscussionsResolved reports whether every discussion started by the bot is resolved.
+func allBotDiscussionsResolved(discussions []client.DiscussionInFileDTO, botUserID string) bool {
+	for _, d := range discussions {
+		if len(d.Comments) == 0 || d.Comments[0].AuthorID != botUserID {
+			continue
+		}
+		if d.IsResolved == nil || !*d.IsResolved {
+			return false
+		}
+	}
+
+	return true
+}
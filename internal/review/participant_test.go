@@ -0,0 +1,149 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+func TestReviewerState(t *testing.T) {
+	posting := config.Posting{LogOnlyBelowConfidence: 0.5}
+
+	tests := []struct {
+		name     string
+		comments []*llm.ReviewComment
+		want     client.ParticipantStateEnum
+	}{
+		{
+			name: "no comments",
+			want: client.ParticipantStateEnumAccepted,
+		},
+		{
+			name:     "only comments below the threshold",
+			comments: []*llm.ReviewComment{{Severity: "medium"}, {Severity: "low"}},
+			want:     client.ParticipantStateEnumAccepted,
+		},
+		{
+			name:     "high severity comment",
+			comments: []*llm.ReviewComment{{Severity: "low"}, {Severity: "high", Confidence: 0.9}},
+			want:     client.ParticipantStateEnumRejected,
+		},
+		{
+			name:     "high severity comment that is only logged",
+			comments: []*llm.ReviewComment{{Severity: "high", Confidence: 0.2}},
+			want:     client.ParticipantStateEnumAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reviewerState(tt.comments, posting, "high"); got != tt.want {
+				t.Fatalf("expected state %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReviewerStateUsesPostedComments(t *testing.T) {
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	if _, err := s.AddSuppression(store.Suppression{Text: "Possible nil pointer dereference."}); err != nil {
+		t.Fatalf("AddSuppression: %v", err)
+	}
+
+	r := &Reviewer{store: s, config: &config.Config{
+		Review:       config.Review{MaxPerReview: 5, Posting: config.Posting{InlineMinSeverity: "high", AggregateMinSeverity: "high"}},
+		Suppressions: config.Suppressions{MinRejections: 2},
+	}}
+	comments := []*llm.ReviewComment{
+		{FilePath: "a.go", Severity: "high", Comment: "Possible nil pointer dereference."},
+		{FilePath: "a.go", Severity: "low", Comment: "Rename the variable."},
+	}
+
+	findings, err := r.postComments(&upsource.Review{}, comments, nil)
+	if err != nil {
+		t.Fatalf("postComments: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("expected no findings, got %d", len(findings))
+	}
+	if got := reviewerState(findings, r.config.Review.Posting, "high"); got != client.ParticipantStateEnumAccepted {
+		t.Fatalf("a suppressed high severity comment must not reject the review, got state %d", got)
+	}
+}
+
+func TestReviewerStateCountsDuplicateFindings(t *testing.T) {
+	open := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/~rpc/getProjectDiscussions" {
+			t.Errorf("unexpected request %s: nothing should be posted", req.URL.Path)
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": client.DiscussionsInProjectDTO{
+			Discussions: []client.DiscussionInFileDTO{{
+				DiscussionID: "d1",
+				Review:       &client.ShortReviewInfoDTO{ReviewID: client.ReviewIdDTO{ProjectID: "p", ReviewID: "r1"}},
+				IsResolved:   &open,
+				Comments:     []client.CommentDTO{{AuthorID: "bot", Text: "Possible nil pointer dereference of the config."}},
+			}},
+		}})
+	}))
+	defer server.Close()
+
+	upsourceClient, err := client.New(client.Options{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+
+	r := &Reviewer{ctx: context.Background(), upsourceClient: upsourceClient, store: s, config: &config.Config{
+		Review: config.Review{
+			MaxPerReview: 5,
+			Posting:      config.Posting{AggregateMinSeverity: "high"},
+			Dedup:        config.Dedup{Enabled: true, Mode: config.DedupModeSuppress, MinSimilarity: 0.6},
+		},
+	}}
+	review := upsource.NewReview(&client.ReviewDescriptorDTO{ReviewID: client.ReviewIdDTO{ProjectID: "p", ReviewID: "r1"}})
+	comments := []*llm.ReviewComment{{FilePath: "a.go", Severity: "high", Comment: "Possible nil pointer dereference of the config."}}
+
+	findings, err := r.postComments(review, comments, nil)
+	if err != nil {
+		t.Fatalf("postComments: %v", err)
+	}
+	if got := reviewerState(findings, r.config.Review.Posting, "high"); got != client.ParticipantStateEnumRejected {
+		t.Fatalf("an open high severity finding raised again must keep the review rejected, got state %d", got)
+	}
+}
+
+func TestAllBotDiscussionsResolved(t *testing.T) {
+	resolved, open := true, false
+	discussions := []client.DiscussionInFileDTO{
+		{IsResolved: &resolved, Comments: []client.CommentDTO{{AuthorID: "bot"}}},
+		{IsResolved: &open, Comments: []client.CommentDTO{{AuthorID: "human"}, {AuthorID: "bot"}}},
+	}
+
+	if !allBotDiscussionsResolved(discussions, "bot") {
+		t.Fatal("expected discussions started by humans to be ignored")
+	}
+
+	discussions = append(discussions, client.DiscussionInFileDTO{Comments: []client.CommentDTO{{AuthorID: "bot"}}})
+	if allBotDiscussionsResolved(discussions, "bot") {
+		t.Fatal("expected an open bot discussion to block acceptance")
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"regexp"
 	"sort"
+	"time"
 
 	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
 	"github.com/groall/upsource-go-client/client"
 
 	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
 	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
 )
 
@@ -19,20 +23,37 @@ type replier struct {
 	ctx            context.Context
 	llmReplier     *llm.Replier
 	botUserID      string
+	botLogin       string
+	store          *store.Store
+	// users resolves the names of the reviewers a discussion is escalated to.
+	users llm.UserNames
+
+	// rereview reviews a review again and posts the comments, for the review command.
+	rereview func(review *upsource.Review) error
+
+	mentionPattern *regexp.Regexp
+	// lastSweep is when the stale discussion sweep last ran.
+	lastSweep time.Time
 }
 
 type replierConfig struct {
 	reviewedLabel      string
 	maxPerThread       int
 	searchReviewsQuery string
+	mentions           config.Mentions
+	commands           config.Commands
+	sweep              config.Sweep
+	suppressions       config.Suppressions
+	escalation         config.Escalation
 }
 
-func newReplier(ctx context.Context, config *replierConfig, upsourceClient *client.Client, llmReplier *llm.Replier) (*replier, error) {
+func newReplier(ctx context.Context, config *replierConfig, upsourceClient *client.Client, llmReplier *llm.Replier, store *store.Store) (*replier, error) {
 	replier := &replier{
 		config:         config,
 		ctx:            ctx,
 		upsourceClient: upsourceClient,
 		llmReplier:     llmReplier,
+		store:          store,
 	}
 
 	return replier, nil
@@ -69,6 +90,18 @@ func (r *replier) replyToOpenThreads() error {
 		}
 	}
 
+	if r.config.mentions.Enabled || r.config.commands.Enabled {
+		if err := r.scanOpenReviews(botUserID); err != nil {
+			log.Printf("Mention and command pass error: %v\n", err)
+		}
+	}
+
+	if r.sweepDue(time.Now()) {
+		if err := r.sweepStaleDiscussions(botUserID); err != nil {
+			log.Printf("Sweep pass error: %v\n", err)
+		}
+	}
+
 	return nil
 }
 
@@ -81,14 +114,34 @@ func (r *replier) replyInReview(review *upsource.Review, botUserID string) error
 		return nil
 	}
 
+	if r.store.IsReviewMuted(review.GetProjectID(), review.GetReviewID().ReviewID) {
+		log.Printf("Skipping muted review %s\n", review.GetBranch())
+		return nil
+	}
+
 	reviewReplier := r.llmReplier.ForReview(review)
 
 	for _, d := range discussions {
+		if escalation := r.config.escalation; escalation.Enabled {
+			if r.isEscalated(review, d, botUserID) {
+				continue // A human reviewer decides.
+			}
+			if last, ok := upsource.ShouldEscalateDiscussion(d, r.config.reviewedLabel, escalation.Label, botUserID, r.config.maxPerThread); ok && !r.isCommand(last.Text) {
+				if err := r.escalate(review, reviewReplier, d, last.CommentID, botUserID); err != nil {
+					log.Printf("Failed to escalate discussion %s: %v\n", d.DiscussionID, err)
+				}
+				continue
+			}
+		}
+
 		last, ok := upsource.ShouldReplyToDiscussion(d, r.config.reviewedLabel, botUserID, r.config.maxPerThread)
 		if !ok {
 			log.Printf("Skipping discussion %s in review %s\n", d.DiscussionID, review.GetBranch())
 			continue
 		}
+		if r.isCommand(last.Text) {
+			continue // Handled by the command pass.
+		}
 
 		reply, lerr := reviewReplier.Reply(d, botUserID)
 		if lerr != nil {
@@ -111,6 +164,23 @@ func (r *replier) replyInReview(review *upsource.Review, botUserID string) error
 				continue
 			}
 			log.Printf("Resolved discussion %s (review %s)\n", d.DiscussionID, review.GetBranch())
+
+			if reply.Resolution == llm.ResolutionWithdrawn && r.config.suppressions.Learn {
+				if err := learnRejection(r.store, review.GetProjectID(), d); err != nil {
+					log.Printf("Failed to store rejected finding of discussion %s: %v\n", d.DiscussionID, err)
+				}
+			}
+			continue
+		}
+
+		if reply.Escalate && r.config.escalation.Enabled {
+			if reply.Comment != "" {
+				// The summary covers the reply that was just posted.
+				d.Comments = append(d.Comments, client.CommentDTO{AuthorID: botUserID, Text: reply.Comment})
+			}
+			if err := r.escalate(review, reviewReplier, d, last.CommentID, botUserID); err != nil {
+				log.Printf("Failed to escalate discussion %s: %v\n", d.DiscussionID, err)
+			}
 		}
 	}
 
@@ -127,6 +197,58 @@ func (r *replier) resolveBotUserID() (string, error) {
 		return "", err
 	}
 	r.botUserID = user.UserID
+	r.botLogin = user.Login
 
 	return r.botUserID, nil
 }
+
+// anchorResolver loads the files discussions are anchored to from Upsource.
+type anchorResolver struct {
+	ctx            context.Context
+	upsourceClient *client.Client
+}
+
+func (a *anchorResolver) ResolveFileAnchor(review *upsource.Review, anchor client.AnchorDTO) (*upsource.FileAnchor, error) {
+	return upsource.ResolveFileAnchor(a.ctx, a.upsourceClient, review, anchor)
+}
+
+// userNames resolves user IDs to display names with Upsource. Names are cached for the lifetime
+// of the process; users Upsource does not know are cached under their ID.
+type userNames struct {
+	ctx            context.Context
+	upsourceClient *client.Client
+	names          map[string]string
+}
+
+func (u *userNames) UserNames(userIDs []string) map[string]string {
+	var missing []string
+	for _, id := range userIDs {
+		if _, ok := u.names[id]; !ok && id != "" {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		names, err := upsource.GetUserNames(u.ctx, u.upsourceClient, missing)
+		if err != nil {
+			log.Printf("Failed to resolve user names: %v\n", err)
+		} else {
+			for _, id := range missing {
+				name, ok := names[id]
+				if !ok {
+					name = id
+				}
+				u.names[id] = name
+			}
+		}
+	}
+
+	out := make(map[string]string, len(userIDs))
+	for _, id := range userIDs {
+		if name, ok := u.names[id]; ok {
+			out[id] = name
+		}
+	}
+
+	return out
+}
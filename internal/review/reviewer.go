@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"sort"
 	"strings"
 
 	"github.com/groall/upsource-go-client/client"
 
+	"github.com/groall/upsource-ai-reviewer/internal/analyzer"
 	"github.com/groall/upsource-ai-reviewer/internal/git"
 	"github.com/groall/upsource-ai-reviewer/internal/llm"
 	"github.com/groall/upsource-ai-reviewer/internal/metrics"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
 	"github.com/groall/upsource-ai-reviewer/pkg/config"
 	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
 )
@@ -23,6 +26,9 @@ type Reviewer struct {
 	ctx            context.Context
 	llmReviewer    *llm.Reviewer
 	replier        *replier
+	analyzer       *analyzer.Runner
+	checkouter     git.Checkouter
+	store          *store.Store
 }
 
 // New creates a new Reviewer instance.
@@ -41,12 +47,29 @@ func New(ctx context.Context, config *config.Config) (*Reviewer, error) {
 		return nil, fmt.Errorf("failed to create GitLab provider: %w", err)
 	}
 
+	stateStore, err := store.Open(config.Store.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+
+	users := &userNames{ctx: ctx, upsourceClient: upsourceClient, names: make(map[string]string)}
 	activeProvider := config.Providers.ActiveLLMProvider()
 	llmReviewerCfg := llm.ReviewConfig{
-		UserPromptTemplate: config.Review.UserPromptTemplate,
-		SystemMessage:      config.Review.SystemMessageTemplate(),
-		MaxPerReview:       config.Review.MaxPerReview,
-		ActiveProvider:     activeProvider,
+		UserPromptTemplate:     config.Review.UserPromptTemplate,
+		SystemMessage:          config.Review.SystemMessageTemplate(),
+		MaxPerReview:           config.Review.MaxPerReview,
+		ActiveProvider:         activeProvider,
+		AnalyzerMode:           config.Analyzers.Mode,
+		MaxFindings:            config.Analyzers.MaxFindings,
+		Redaction:              config.Redaction,
+		Critique:               config.Review.Critique,
+		Summary:                config.Review.Summary,
+		PromptFragments:        config.Review.PromptFragments,
+		Tracker:                config.Tracker,
+		Suppressions:           &activeSuppressions{store: stateStore, minRejections: config.Suppressions.MinRejections},
+		MaxSuppressionExamples: config.Suppressions.MaxExamples,
+		Style:                  config.Style,
+		Users:                  users,
 	}
 	llmReviewer, err := llm.New(ctx, llmReviewerCfg, config.Providers, gitlabProvider)
 	if err != nil {
@@ -54,28 +77,54 @@ func New(ctx context.Context, config *config.Config) (*Reviewer, error) {
 	}
 
 	llmReplierCfg := llm.ReplyConfig{
-		SystemMessage:  config.Replies.SystemMessage,
-		ActiveProvider: activeProvider,
+		SystemMessage:           config.Replies.SystemMessage,
+		MentionSystemMessage:    config.Replies.Mentions.SystemMessage,
+		RecheckSystemMessage:    config.Replies.Sweep.SystemMessage,
+		FeedbackSystemMessage:   config.Feedback.SystemMessage,
+		EscalationSystemMessage: config.Replies.Escalation.SystemMessage,
+		ActiveProvider:          activeProvider,
+		Style:                   config.Style,
+		Memory:                  config.Replies.Memory,
+		Threads:                 stateStore,
+		Users:                   users,
+	}
+	llmReplier, err := llm.NewReplier(llmReviewer, llmReplierCfg, &anchorResolver{ctx: ctx, upsourceClient: upsourceClient})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LLM replier: %w", err)
 	}
-	llmReplier := llm.NewReplier(llmReviewer, llmReplierCfg)
 
 	replierConfig := &replierConfig{
 		reviewedLabel:      config.Upsource.ReviewedLabel,
 		maxPerThread:       config.Replies.MaxPerThread,
 		searchReviewsQuery: config.Upsource.Query,
+		mentions:           config.Replies.Mentions,
+		commands:           config.Replies.Commands,
+		sweep:              config.Replies.Sweep,
+		suppressions:       config.Suppressions,
+		escalation:         config.Replies.Escalation,
 	}
-	replier, err := newReplier(ctx, replierConfig, upsourceClient, llmReplier)
+	replier, err := newReplier(ctx, replierConfig, upsourceClient, llmReplier, stateStore)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create replier: %w", err)
 	}
 
-	return &Reviewer{
+	reviewer := &Reviewer{
 		upsourceClient: upsourceClient,
 		llmReviewer:    llmReviewer,
 		replier:        replier,
 		config:         config,
 		ctx:            ctx,
-	}, nil
+		store:          stateStore,
+	}
+	replier.rereview = reviewer.processReview
+	replier.users = users
+
+	if config.Analyzers.Enabled {
+		reviewer.analyzer = analyzer.New(config.Analyzers)
+		reviewer.checkouter = gitlabProvider
+	}
+
+	return reviewer, nil
 }
 
 // Run starts the AI Reviewer process, fetching reviews from Upsource, generating comments, and posting them back.
@@ -87,7 +136,6 @@ func (r *Reviewer) Run() error {
 	projects, reviewsByProject := groupReviewsByProject(reviews)
 	log.Printf("Found %d reviews to process across %d projects.\n", len(reviews), len(projects))
 
-	var comments []*llm.ReviewComment
 	for _, projectID := range projects {
 		projectReviews := reviewsByProject[projectID]
 		sort.Slice(projectReviews, func(i, j int) bool {
@@ -96,18 +144,8 @@ func (r *Reviewer) Run() error {
 		log.Printf("Processing %d reviews in project %s.\n", len(projectReviews), projectID)
 
 		for _, review := range projectReviews {
-			if comments, err = r.doReview(review); err != nil {
+			if err := r.processReview(review); err != nil {
 				log.Printf("Error processing review %s: %v\n", review.GetBranch(), err)
-				continue
-			}
-
-			if len(comments) == 0 {
-				log.Printf("AI Reviewer found no issues to comment on for %s.\n", review.GetBranch())
-				continue
-			}
-
-			if err := r.postComments(review, comments); err != nil {
-				log.Printf("Error posting comments for review %s: %v\n", review.GetBranch(), err)
 			}
 		}
 	}
@@ -118,6 +156,44 @@ func (r *Reviewer) Run() error {
 		}
 	}
 
+	if r.config.Review.Participant.Enabled {
+		if err := r.acceptResolvedReviews(); err != nil {
+			log.Printf("Error accepting resolved reviews: %v", err)
+		}
+	}
+
+	if r.config.Feedback.Enabled {
+		if err := r.collectFeedback(); err != nil {
+			log.Printf("Error collecting feedback: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// processReview reviews a change and posts the comments.
+func (r *Reviewer) processReview(review *upsource.Review) error {
+	result, err := r.doReview(review)
+	if err != nil {
+		return err
+	}
+
+	var findings []*llm.ReviewComment
+	if len(result.Comments) == 0 && result.Summary == nil {
+		log.Printf("AI Reviewer found no issues to comment on for %s.\n", review.GetBranch())
+	} else {
+		findings, err = r.postComments(review, result.Comments, result.Summary)
+		if err != nil {
+			return fmt.Errorf("error posting comments: %w", err)
+		}
+	}
+
+	// The state depends on the findings after thresholds and suppressions. Duplicates of open discussions
+	// still count: a re-review that only repeats an unresolved high severity finding keeps the review rejected.
+	if r.config.Review.Participant.Enabled {
+		r.updateParticipantState(review, findings)
+	}
+
 	return nil
 }
 
@@ -136,10 +212,17 @@ func groupReviewsByProject(reviews []*upsource.Review) ([]string, map[string][]*
 	return projects, byProject
 }
 
-func (r *Reviewer) doReview(review *upsource.Review) ([]*llm.ReviewComment, error) {
+func (r *Reviewer) doReview(review *upsource.Review) (*llm.ReviewResult, error) {
 	log.Printf("Processing review for the branch %s.\n", review.GetBranch())
 
-	comments, err := r.llmReviewer.Do(review)
+	findings := r.runAnalyzers(review)
+
+	botUserID, err := r.replier.resolveBotUserID()
+	if err != nil {
+		log.Printf("Failed to resolve bot user id, it may be listed among the reviewers: %v\n", err)
+	}
+
+	result, err := r.llmReviewer.Do(review, findings, botUserID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting review comments for %s: %w", review.GetBranch(), err)
 	}
@@ -149,7 +232,33 @@ func (r *Reviewer) doReview(review *upsource.Review) ([]*llm.ReviewComment, erro
 	}
 	metrics.DefaultRecorder.RecordReviewReviewed()
 
-	return comments, nil
+	return result, nil
+}
+
+// runAnalyzers checks out the review branch and runs the configured static analyzers on it.
+// Analyzer problems are logged and never block the LLM review.
+func (r *Reviewer) runAnalyzers(review *upsource.Review) []analyzer.Finding {
+	if r.analyzer == nil {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp(r.config.Analyzers.CheckoutDir, "review-*")
+	if err != nil {
+		log.Printf("Failed to create checkout directory for %s: %v\n", review.GetBranch(), err)
+		return nil
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("Failed to remove checkout directory %s: %v\n", dir, err)
+		}
+	}()
+
+	if err := r.checkouter.Checkout(r.ctx, review, dir); err != nil {
+		log.Printf("Skipping static analysis for %s: %v\n", review.GetBranch(), err)
+		return nil
+	}
+
+	return r.analyzer.Run(r.ctx, dir)
 }
 
 // listReviews fetches reviews from Upsource based on the configured query.
@@ -159,19 +268,47 @@ func (r *Reviewer) listReviews() ([]*upsource.Review, error) {
 		return nil, fmt.Errorf("failed to list reviews: %w", err)
 	}
 
-	return reviews, nil
+	unmuted := reviews[:0]
+	for _, review := range reviews {
+		if r.store.IsReviewMuted(review.GetProjectID(), review.GetReviewID().ReviewID) {
+			log.Printf("Skipping muted review %s.\n", review.GetBranch())
+			continue
+		}
+		unmuted = append(unmuted, review)
+	}
+
+	return unmuted, nil
 }
 
 // postComments posts review comments to Upsource, splitting high severity comments into separate discussions if configured.
-func (r *Reviewer) postComments(review *upsource.Review, comments []*llm.ReviewComment) error {
-	comments = sortAndCapComments(comments, r.config.Review.MaxPerReview)
+// Comments below the posting thresholds are only logged, comments already raised in the review are not posted again.
+// With a summary, the comments that are not posted inline are listed in the summary discussion.
+// It returns the findings of the review: the comments that passed the posting thresholds and suppressions,
+// including those already raised in an open discussion, which were not posted again.
+func (r *Reviewer) postComments(review *upsource.Review, comments []*llm.ReviewComment, summary *llm.ReviewSummary) ([]*llm.ReviewComment, error) {
+	posting := r.config.Review.Posting
+
+	r.locateSnippets(review, comments)
+
+	var kept []*llm.ReviewComment
+	for _, comment := range comments {
+		if postingTargetFor(comment, posting) == postingTargetLog {
+			log.Printf("Not posting %s comment (confidence %.2f) on %s:%d below the posting thresholds: %s\n",
+				comment.Severity, comment.Confidence, comment.FilePath, comment.LineNumber, comment.Comment)
+			continue
+		}
+		kept = append(kept, comment)
+	}
+	kept = r.suppressComments(review, kept)
+	findings := kept
+	kept = r.deduplicateComments(review, kept)
+	kept = sortAndCapComments(kept, r.config.Review.MaxPerReview)
 
 	var postInOneComments []*llm.ReviewComment
 	var inlineComments []*llm.ReviewComment
 
-	for _, comment := range comments {
-		thereIsLine := comment.LineNumber > 0 && comment.FilePath != "" && comment.LineVerified
-		if thereIsLine {
+	for _, comment := range kept {
+		if postingTargetFor(comment, posting) == postingTargetInline {
 			inlineComments = append(inlineComments, comment)
 		} else {
 			postInOneComments = append(postInOneComments, comment)
@@ -181,17 +318,60 @@ func (r *Reviewer) postComments(review *upsource.Review, comments []*llm.ReviewC
 	for _, comment := range inlineComments {
 		err := r.createDiscussion(comment, review)
 		if err != nil {
-			return fmt.Errorf("failed to post inline comment to %s:%d -> %s: %w", comment.FilePath, comment.LineNumber, comment.Comment, err)
+			return nil, fmt.Errorf("failed to post inline comment to %s:%d -> %s: %w", comment.FilePath, comment.LineNumber, comment.Comment, err)
 		}
 	}
 
+	if summary != nil {
+		if err := r.createSummaryDiscussion(summary, postInOneComments, review); err != nil {
+			return nil, fmt.Errorf("failed to post summary to review %s: %w", review.GetBranch(), err)
+		}
+		return findings, nil
+	}
+
 	if len(postInOneComments) > 0 {
 		if err := r.createDiscussionWithoutLine(postInOneComments, review); err != nil {
-			return fmt.Errorf("failed to post comments to review %s: %w", review.GetBranch(), err)
+			return nil, fmt.Errorf("failed to post comments to review %s: %w", review.GetBranch(), err)
 		}
 	}
 
-	return nil
+	return findings, nil
+}
+
+type postingTarget int
+
+const (
+	postingTargetLog postingTarget = iota
+	postingTargetInline
+	postingTargetAggregate
+)
+
+// postingTargetFor decides whether a comment is posted inline, collected in the aggregated discussion or only logged.
+func postingTargetFor(comment *llm.ReviewComment, posting config.Posting) postingTarget {
+	if comment.Confidence > 0 && comment.Confidence < posting.LogOnlyBelowConfidence {
+		return postingTargetLog
+	}
+
+	thereIsLine := comment.LineNumber > 0 && comment.FilePath != "" && comment.LineVerified
+	if thereIsLine && meetsThreshold(comment, posting.InlineMinSeverity, posting.InlineMinConfidence) {
+		return postingTargetInline
+	}
+
+	if meetsThreshold(comment, posting.AggregateMinSeverity, posting.AggregateMinConfidence) {
+		return postingTargetAggregate
+	}
+
+	return postingTargetLog
+}
+
+// meetsThreshold reports whether a comment is at least minSeverity and minConfidence.
+// Empty minSeverity, zero minConfidence and unknown (zero) comment confidence pass.
+func meetsThreshold(comment *llm.ReviewComment, minSeverity string, minConfidence float64) bool {
+	if minSeverity != "" && llm.SeverityRank(comment.Severity) > llm.SeverityRank(minSeverity) {
+		return false
+	}
+
+	return comment.Confidence == 0 || comment.Confidence >= minConfidence
 }
 
 // sortAndCapComments sorts and caps comments.
@@ -205,8 +385,8 @@ func sortAndCapComments(comments []*llm.ReviewComment, maxPerReview int) []*llm.
 		a := sorted[i]
 		b := sorted[j]
 
-		aRank := severityRank(a.Severity)
-		bRank := severityRank(b.Severity)
+		aRank := llm.SeverityRank(a.Severity)
+		bRank := llm.SeverityRank(b.Severity)
 		if aRank != bRank {
 			return aRank < bRank
 		}
@@ -233,24 +413,11 @@ func sortAndCapComments(comments []*llm.ReviewComment, maxPerReview int) []*llm.
 	return sorted
 }
 
-func severityRank(severity string) int {
-	switch strings.ToLower(severity) {
-	case llm.SeverityHigh:
-		return 0
-	case llm.SeverityMedium:
-		return 1
-	case llm.SeverityLow:
-		return 2
-	default:
-		return 3
-	}
-}
-
 // createDiscussionWithoutLine posts comments to a single discussion to Upsource without a link to a file and a line in it
 func (r *Reviewer) createDiscussionWithoutLine(comments []*llm.ReviewComment, review *upsource.Review) error {
 	discussionText := generateLowPriorityComment(comments)
 	if len(discussionText) > 0 {
-		err := upsource.CreateDiscussion(r.ctx, r.upsourceClient, r.config.Upsource.ReviewedLabel, upsource.CreateDiscussionRequest{
+		_, err := upsource.CreateDiscussion(r.ctx, r.upsourceClient, r.config.Upsource.ReviewedLabel, upsource.CreateDiscussionRequest{
 			Review:  review,
 			Comment: discussionText,
 			File:    "",
@@ -265,30 +432,198 @@ func (r *Reviewer) createDiscussionWithoutLine(comments []*llm.ReviewComment, re
 	return nil
 }
 
+// locateSnippets anchors comments whose reported line is not a changed line to a nearby line of the full file
+// that contains their snippet, so they can be posted inline instead of in the aggregated discussion.
+func (r *Reviewer) locateSnippets(review *upsource.Review, comments []*llm.ReviewComment) {
+	for _, comment := range comments {
+		if comment.LineVerified || comment.ReportedLine <= 0 || comment.FilePath == "" || comment.Snippet == "" {
+			continue
+		}
+
+		line, ok, err := upsource.LocateSnippet(r.ctx, r.upsourceClient, review, comment.FilePath, comment.ReportedLine, comment.Snippet, comment.Side == llm.SideOld)
+		if err != nil {
+			log.Printf("Failed to locate the snippet of the comment on %s:%d: %v\n", comment.FilePath, comment.ReportedLine, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		log.Printf("Comment on %s:%d is anchored to line %d of the file by its snippet\n", comment.FilePath, comment.ReportedLine, line)
+		comment.LineNumber = line
+		comment.LineVerified = true
+	}
+}
+
 // createDiscussion posts a single discussion to Upsource.
 func (r *Reviewer) createDiscussion(comment *llm.ReviewComment, review *upsource.Review) error {
-	err := upsource.CreateDiscussion(r.ctx, r.upsourceClient, r.config.Upsource.ReviewedLabel, upsource.CreateDiscussionRequest{
+	discussion, err := upsource.CreateDiscussion(r.ctx, r.upsourceClient, r.config.Upsource.ReviewedLabel, upsource.CreateDiscussionRequest{
 		Review:  review,
-		Comment: comment.Comment,
+		Comment: commentBody(comment),
 		File:    comment.FilePath,
 		Line:    comment.LineNumber,
+		EndLine: comment.EndLineNumber,
+		OldSide: comment.Side == llm.SideOld,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to post low priority comment to review %s: %w", review.GetBranch(), err)
 	}
 	metrics.DefaultRecorder.RecordReviewCommentsPosted(1)
+	r.recordFeedback(review, discussion.DiscussionID, comment)
+
+	return nil
+}
+
+// createSummaryDiscussion posts the review summary together with the comments that are not posted inline
+// as a general discussion and stars it, so it stays at the top of the review. On a re-review the bot's
+// earlier summary is updated instead, so summaries do not pile up.
+func (r *Reviewer) createSummaryDiscussion(summary *llm.ReviewSummary, comments []*llm.ReviewComment, review *upsource.Review) error {
+	text := generateSummaryComment(summary, comments)
+
+	previous, err := r.previousSummary(review)
+	if err != nil {
+		log.Printf("Failed to find the previous summary of review %s: %v\n", review.GetBranch(), err)
+	}
+	if previous != nil {
+		first := previous.Comments[0]
+		if first.Text == text {
+			log.Printf("Summary of review %s is unchanged\n", review.GetBranch())
+			return nil
+		}
+		if err := upsource.UpdateDiscussionComment(r.ctx, r.upsourceClient, review.GetProjectID(), previous.DiscussionID, first.CommentID, text); err != nil {
+			return err
+		}
+		metrics.DefaultRecorder.RecordReviewCommentsPosted(len(comments))
+		return nil
+	}
+
+	discussion, err := upsource.CreateDiscussion(r.ctx, r.upsourceClient, r.config.Upsource.ReviewedLabel, upsource.CreateDiscussionRequest{
+		Review:  review,
+		Comment: text,
+	})
+	if err != nil {
+		return err
+	}
+	metrics.DefaultRecorder.RecordReviewCommentsPosted(len(comments))
+
+	if err := upsource.StarDiscussion(r.ctx, r.upsourceClient, review.GetProjectID(), discussion.DiscussionID); err != nil {
+		log.Printf("Failed to star summary discussion in review %s: %v\n", review.GetBranch(), err)
+	}
 
 	return nil
 }
 
+// previousSummary returns the unresolved summary discussion the bot posted in an earlier review of the change, or nil.
+func (r *Reviewer) previousSummary(review *upsource.Review) (*client.DiscussionInFileDTO, error) {
+	botUserID, err := r.replier.resolveBotUserID()
+	if err != nil {
+		return nil, fmt.Errorf("resolve bot user id: %w", err)
+	}
+
+	discussions, err := upsource.ListReviewDiscussions(r.ctx, r.upsourceClient, review)
+	if err != nil {
+		return nil, fmt.Errorf("list discussions: %w", err)
+	}
+
+	return findSummaryDiscussion(discussions, botUserID), nil
+}
+
+// findSummaryDiscussion returns the latest unresolved general discussion started by the bot with a review summary, or nil.
+func findSummaryDiscussion(discussions []client.DiscussionInFileDTO, botUserID string) *client.DiscussionInFileDTO {
+	var found *client.DiscussionInFileDTO
+	for i, d := range discussions {
+		if d.Anchor.FileID != "" || d.IsResolved != nil && *d.IsResolved || len(d.Comments) == 0 {
+			continue
+		}
+		first := d.Comments[0]
+		if first.AuthorID == botUserID && strings.HasPrefix(first.Text, summaryHeader) {
+			found = &discussions[i]
+		}
+	}
+
+	return found
+}
+
 // generateLowPriorityComment creates a formatted string for low and medium priority comments.
 func generateLowPriorityComment(comments []*llm.ReviewComment) string {
 	var commentsBuilder strings.Builder
 	commentsBuilder.WriteString("### Low-Medium Priority Comments (AI generated):\n\n")
+	writeCommentList(&commentsBuilder, comments)
+
+	return commentsBuilder.String()
+}
+
+// summaryHeader starts the summary discussion and identifies the bot's earlier summaries.
+const summaryHeader = "### Review Summary (AI generated)\n\n"
+
+// generateSummaryComment creates a formatted string for the review summary followed by the other comments.
+func generateSummaryComment(summary *llm.ReviewSummary, comments []*llm.ReviewComment) string {
+	var b strings.Builder
+	b.WriteString(summaryHeader)
+
+	var status []string
+	if summary.Verdict != "" {
+		status = append(status, fmt.Sprintf("**Verdict:** %s", summary.Verdict))
+	}
+	if summary.RiskLevel != "" {
+		status = append(status, fmt.Sprintf("**Risk:** %s", summary.RiskLevel))
+	}
+	if len(status) > 0 {
+		b.WriteString(strings.Join(status, " | ") + "\n\n")
+	}
+
+	if summary.Description != "" {
+		b.WriteString(summary.Description + "\n\n")
+	}
 
+	if len(summary.RiskAreas) > 0 {
+		b.WriteString("**Risk areas**\n\n")
+		for _, area := range summary.RiskAreas {
+			b.WriteString("- " + area + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if summary.TestCoverage != "" {
+		b.WriteString("**Test coverage**\n\n" + summary.TestCoverage + "\n\n")
+	}
+
+	if len(comments) > 0 {
+		b.WriteString("#### Other comments\n\n")
+		writeCommentList(&b, comments)
+	}
+
+	return b.String()
+}
+
+func writeCommentList(b *strings.Builder, comments []*llm.ReviewComment) {
 	for _, comment := range comments {
-		commentsBuilder.WriteString(fmt.Sprintf("**%s** %s:%d %s\n\n", strings.ToUpper(comment.Severity), comment.FilePath, comment.LineNumber, comment.Comment))
+		b.WriteString(fmt.Sprintf("**%s** %s:%d %s\n\n", strings.ToUpper(comment.Severity), comment.FilePath, comment.LineNumber, commentBody(comment)))
 	}
+}
 
-	return commentsBuilder.String()
+// commentBody returns the comment text followed by its suggested fix, if any.
+func commentBody(comment *llm.ReviewComment) string {
+	if comment.Suggestion == nil {
+		return comment.Comment
+	}
+
+	return comment.Comment + "\n\n" + formatSuggestion(comment.Suggestion)
+}
+
+// formatSuggestion renders a suggested fix as a fenced diff block of the replaced and the new lines.
+func formatSuggestion(suggestion *llm.Suggestion) string {
+	var b strings.Builder
+	b.WriteString("```diff\n")
+	for _, line := range strings.Split(suggestion.OldText, "\n") {
+		b.WriteString("-" + line + "\n")
+	}
+	if suggestion.NewText != "" {
+		for _, line := range strings.Split(strings.TrimSuffix(suggestion.NewText, "\n"), "\n") {
+			b.WriteString("+" + line + "\n")
+		}
+	}
+	b.WriteString("```")
+
+	return b.String()
 }
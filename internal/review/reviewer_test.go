@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-go-client/client"
 )
 
 func TestSortAndCapComments(t *testing.T) {
@@ -30,3 +32,139 @@ func TestSortAndCapComments(t *testing.T) {
 		t.Fatalf("expected third comment m1, got %q", got[2].Comment)
 	}
 }
+
+func TestPostingTargetFor(t *testing.T) {
+	posting := config.Posting{
+		InlineMinSeverity:      "medium",
+		InlineMinConfidence:    0.7,
+		AggregateMinSeverity:   "low",
+		AggregateMinConfidence: 0.5,
+		LogOnlyBelowConfidence: 0.3,
+	}
+
+	tests := []struct {
+		name    string
+		comment *llm.ReviewComment
+		want    postingTarget
+	}{
+		{
+			name:    "inline when above inline thresholds",
+			comment: &llm.ReviewComment{Severity: "high", FilePath: "a.go", LineNumber: 1, LineVerified: true, Confidence: 0.9},
+			want:    postingTargetInline,
+		},
+		{
+			name:    "unknown confidence passes",
+			comment: &llm.ReviewComment{Severity: "medium", FilePath: "a.go", LineNumber: 1, LineVerified: true},
+			want:    postingTargetInline,
+		},
+		{
+			name:    "aggregate when severity is below inline threshold",
+			comment: &llm.ReviewComment{Severity: "low", FilePath: "a.go", LineNumber: 1, LineVerified: true, Confidence: 0.9},
+			want:    postingTargetAggregate,
+		},
+		{
+			name:    "aggregate when confidence is below inline threshold",
+			comment: &llm.ReviewComment{Severity: "high", FilePath: "a.go", LineNumber: 1, LineVerified: true, Confidence: 0.6},
+			want:    postingTargetAggregate,
+		},
+		{
+			name:    "aggregate without verified line",
+			comment: &llm.ReviewComment{Severity: "high", FilePath: "a.go", LineNumber: 1, Confidence: 0.9},
+			want:    postingTargetAggregate,
+		},
+		{
+			name:    "log when below aggregate threshold",
+			comment: &llm.ReviewComment{Severity: "low", Confidence: 0.4},
+			want:    postingTargetLog,
+		},
+		{
+			name:    "log only below floor",
+			comment: &llm.ReviewComment{Severity: "high", FilePath: "a.go", LineNumber: 1, LineVerified: true, Confidence: 0.2},
+			want:    postingTargetLog,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postingTargetFor(tt.comment, posting); got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPostingTargetForWithoutThresholds(t *testing.T) {
+	inline := &llm.ReviewComment{Severity: "low", FilePath: "a.go", LineNumber: 1, LineVerified: true, Confidence: 0.1}
+	if got := postingTargetFor(inline, config.Posting{}); got != postingTargetInline {
+		t.Fatalf("expected inline, got %d", got)
+	}
+
+	aggregate := &llm.ReviewComment{Severity: "low", Confidence: 0.1}
+	if got := postingTargetFor(aggregate, config.Posting{}); got != postingTargetAggregate {
+		t.Fatalf("expected aggregate, got %d", got)
+	}
+}
+
+func TestCommentBodyRendersSuggestion(t *testing.T) {
+	comment := &llm.ReviewComment{
+		Comment: "Check the error.",
+		Suggestion: &llm.Suggestion{
+			StartLine: 3,
+			EndLine:   3,
+			OldText:   "\tdoIt()",
+			NewText:   "\tif err := doIt(); err != nil {\n\t\treturn err\n\t}\n",
+		},
+	}
+
+	want := "Check the error.\n\n```diff\n-\tdoIt()\n+\tif err := doIt(); err != nil {\n+\t\treturn err\n+\t}\n```"
+	if got := commentBody(comment); got != want {
+		t.Fatalf("unexpected comment body:\n%s", got)
+	}
+
+	if got := commentBody(&llm.ReviewComment{Comment: "Plain."}); got != "Plain." {
+		t.Fatalf("unexpected comment body: %q", got)
+	}
+}
+
+func TestGenerateSummaryComment(t *testing.T) {
+	summary := &llm.ReviewSummary{
+		Description:  "Adds retries to the HTTP client.",
+		RiskLevel:    "medium",
+		RiskAreas:    []string{"retry storms", "timeouts"},
+		TestCoverage: "No tests for the backoff.",
+		Verdict:      llm.VerdictNeedsWork,
+	}
+	comments := []*llm.ReviewComment{{Severity: "low", FilePath: "a.go", Comment: "Rename `n`."}}
+
+	want := "### Review Summary (AI generated)\n\n" +
+		"**Verdict:** needs work | **Risk:** medium\n\n" +
+		"Adds retries to the HTTP client.\n\n" +
+		"**Risk areas**\n\n- retry storms\n- timeouts\n\n" +
+		"**Test coverage**\n\nNo tests for the backoff.\n\n" +
+		"#### Other comments\n\n**LOW** a.go:0 Rename `n`.\n\n"
+
+	if got := generateSummaryComment(summary, comments); got != want {
+		t.Fatalf("unexpected summary comment:\n%s", got)
+	}
+}
+
+func TestFindSummaryDiscussion(t *testing.T) {
+	resolved := true
+	summary := func(id, author string) client.DiscussionInFileDTO {
+		return client.DiscussionInFileDTO{DiscussionID: id, Comments: []client.CommentDTO{{AuthorID: author, Text: summaryHeader + "Looks fine."}}}
+	}
+	resolvedSummary := summary("resolved", "bot")
+	resolvedSummary.IsResolved = &resolved
+	inline := summary("inline", "bot")
+	inline.Anchor = client.AnchorDTO{FileID: "/a.go"}
+	other := client.DiscussionInFileDTO{DiscussionID: "other", Comments: []client.CommentDTO{{AuthorID: "bot", Text: "### Low-Medium Priority Comments (AI generated):"}}}
+
+	discussions := []client.DiscussionInFileDTO{summary("first", "bot"), summary("latest", "bot"), summary("human", "dev"), resolvedSummary, inline, other}
+	if got := findSummaryDiscussion(discussions, "bot"); got == nil || got.DiscussionID != "latest" {
+		t.Fatalf("findSummaryDiscussion = %v, want discussion latest", got)
+	}
+
+	if got := findSummaryDiscussion(discussions[2:], "bot"); got != nil {
+		t.Fatalf("findSummaryDiscussion = %v, want nil", got)
+	}
+}
@@ -0,0 +1,128 @@
+package review
+
+import (
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// suppressionMinSimilarity is the share of common words from which a comment is considered the suppressed kind of issue.
+const suppressionMinSimilarity = 0.5
+
+// maxFindingLength limits the stored description of a rejected finding.
+const maxFindingLength = 300
+
+// activeSuppressions returns the suppressions in effect, leaving out learned ones with too few rejections.
+type activeSuppressions struct {
+	store         *store.Store
+	minRejections int
+}
+
+func (a *activeSuppressions) Suppressions(projectID, path string) []store.Suppression {
+	var out []store.Suppression
+	for _, sup := range a.store.Suppressions(projectID, path) {
+		if sup.Active(a.minRejections) {
+			out = append(out, sup)
+		}
+	}
+
+	return out
+}
+
+// suppressComments drops comments matching a suppression stored for their file or project.
+func (r *Reviewer) suppressComments(review *upsource.Review, comments []*llm.ReviewComment) []*llm.ReviewComment {
+	kept := make([]*llm.ReviewComment, 0, len(comments))
+	for _, comment := range comments {
+		if id, ok := r.suppressedBy(review.GetProjectID(), comment); ok {
+			log.Printf("Not posting comment on %s:%d suppressed by %s: %s\n", comment.FilePath, comment.LineNumber, id, comment.Comment)
+			continue
+		}
+		kept = append(kept, comment)
+	}
+
+	return kept
+}
+
+// suppressedBy returns the ID of the active suppression matching the comment.
+func (r *Reviewer) suppressedBy(projectID string, comment *llm.ReviewComment) (string, bool) {
+	active := &activeSuppressions{store: r.store, minRejections: r.config.Suppressions.MinRejections}
+	for _, sup := range active.Suppressions(projectID, comment.FilePath) {
+		if textSimilarity(comment.Comment, sup.Text) >= suppressionMinSimilarity {
+			return sup.ID, true
+		}
+	}
+
+	return "", false
+}
+
+// learnRejection records that developers rejected the finding that started a bot discussion.
+// A similar learned suppression in the project collects the rejection and is widened to the whole
+// project when it was rejected in another file; otherwise a new learned suppression is stored.
+func learnRejection(st *store.Store, projectID string, d client.DiscussionInFileDTO) error {
+	if len(d.Comments) == 0 {
+		return nil
+	}
+
+	text := normalizeFinding(d.Comments[0].Text)
+	path := strings.TrimPrefix(d.Anchor.FileID, "/")
+	if text == "" {
+		return nil
+	}
+
+	for _, sup := range st.AllSuppressions() {
+		if !sup.Learned || sup.ProjectID != projectID || textSimilarity(sup.Text, text) < suppressionMinSimilarity {
+			continue
+		}
+		if slices.Contains(sup.Rejections, d.DiscussionID) {
+			return nil
+		}
+
+		sup.Rejections = append(sup.Rejections, d.DiscussionID)
+		if sup.Path != path {
+			sup.Path = ""
+		}
+		log.Printf("Finding rejected again in discussion %s, %d rejections for suppression %s\n", d.DiscussionID, len(sup.Rejections), sup.ID)
+		return st.UpdateSuppression(sup)
+	}
+
+	sup, err := st.AddSuppression(store.Suppression{
+		ProjectID:  projectID,
+		Path:       path,
+		Text:       text,
+		Learned:    true,
+		Rejections: []string{d.DiscussionID},
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Finding rejected in discussion %s, stored as suppression %s\n", d.DiscussionID, sup.ID)
+
+	return nil
+}
+
+var markdownMarkup = regexp.MustCompile("[`*_#>]+")
+
+// normalizeFinding turns a review comment into a short description of the finding: code blocks
+// and markdown are removed, whitespace is collapsed and the text is cut to maxFindingLength.
+func normalizeFinding(text string) string {
+	text = fencedCodeBlock.ReplaceAllString(text, "")
+	text = markdownMarkup.ReplaceAllString(text, "")
+	text = strings.Join(strings.Fields(text), " ")
+
+	if runes := []rune(text); len(runes) > maxFindingLength {
+		text = string(runes[:maxFindingLength])
+		if i := strings.LastIndex(text, " "); i > 0 {
+			text = text[:i]
+		}
+		text += "…"
+	}
+
+	return text
+}
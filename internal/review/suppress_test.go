@@ -0,0 +1,85 @@
+package review
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/groall/upsource-go-client/client"
+
+	"github.com/groall/upsource-ai-reviewer/internal/llm"
+	"github.com/groall/upsource-ai-reviewer/internal/store"
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+func TestSuppressComments(t *testing.T) {
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	if _, err := s.AddSuppression(store.Suppression{ProjectID: "", Path: "a.go", Text: "The error returned by Close is ignored."}); err != nil {
+		t.Fatalf("AddSuppression: %v", err)
+	}
+	// A learned suppression with too few rejections is not in effect yet.
+	if _, err := s.AddSuppression(store.Suppression{Text: "Possible nil pointer dereference.", Learned: true, Rejections: []string{"d1"}}); err != nil {
+		t.Fatalf("AddSuppression: %v", err)
+	}
+
+	r := &Reviewer{store: s, config: &config.Config{Suppressions: config.Suppressions{MinRejections: 2}}}
+	comments := []*llm.ReviewComment{
+		{FilePath: "a.go", Comment: "Error returned by Close is ignored here."},
+		{FilePath: "b.go", Comment: "Error returned by Close is ignored here."},
+		{FilePath: "a.go", Comment: "Possible nil pointer dereference."},
+	}
+
+	kept := r.suppressComments(&upsource.Review{}, comments)
+	if len(kept) != 2 || kept[0] != comments[1] || kept[1] != comments[2] {
+		t.Fatalf("suppressComments kept %v, want the comments on b.go and the nil dereference", kept)
+	}
+}
+
+func TestLearnRejection(t *testing.T) {
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	rejected := func(id, file, text string) client.DiscussionInFileDTO {
+		return client.DiscussionInFileDTO{
+			DiscussionID: id,
+			Anchor:       client.AnchorDTO{FileID: file},
+			Comments:     []client.CommentDTO{{Text: text}, {Text: "We ignore it on purpose."}},
+		}
+	}
+
+	for _, d := range []client.DiscussionInFileDTO{
+		rejected("d1", "/a.go", "The error returned by `Close` is ignored."),
+		rejected("d1", "/a.go", "The error returned by `Close` is ignored."), // Counted once per discussion.
+		rejected("d2", "/b.go", "Error returned by Close is ignored here."),
+	} {
+		if err := learnRejection(s, "proj", d); err != nil {
+			t.Fatalf("learnRejection: %v", err)
+		}
+	}
+
+	sups := s.AllSuppressions()
+	if len(sups) != 1 {
+		t.Fatalf("got %d suppressions, want 1", len(sups))
+	}
+	sup := sups[0]
+	if !sup.Learned || sup.Text != "The error returned by Close is ignored." || sup.Path != "" || len(sup.Rejections) != 2 {
+		t.Fatalf("learned suppression = %+v, want a project-wide suppression with 2 rejections", sup)
+	}
+}
+
+func TestNormalizeFinding(t *testing.T) {
+	got := normalizeFinding("**Error** from `Close`\n\n```go\n_ = f.Close()\n```\nis  ignored.")
+	if got != "Error from Close is ignored." {
+		t.Fatalf("normalizeFinding = %q", got)
+	}
+
+	long := normalizeFinding(strings.Repeat("word ", 100))
+	if len([]rune(long)) > maxFindingLength+1 || !strings.HasSuffix(long, "word…") {
+		t.Fatalf("normalizeFinding did not shorten the text: %q", long)
+	}
+}
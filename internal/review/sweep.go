@@ -0,0 +1,83 @@
+package review
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/upsource"
+)
+
+// sweepDue reports whether the stale discussion sweep should run at now and, if so, records the run.
+func (r *replier) sweepDue(now time.Time) bool {
+	if !r.config.sweep.Enabled {
+		return false
+	}
+
+	interval := time.Duration(r.config.sweep.IntervalMinutes) * time.Minute
+	if !r.lastSweep.IsZero() && now.Sub(r.lastSweep) < interval {
+		return false
+	}
+	r.lastSweep = now
+
+	return true
+}
+
+// sweepStaleDiscussions resolves unanswered bot discussions in already-reviewed reviews whose
+// commented lines changed so that the issue no longer applies. Errors are logged per review.
+func (r *replier) sweepStaleDiscussions(botUserID string) error {
+	reviews, err := upsource.ListReviewedReviews(r.ctx, r.upsourceClient, r.config.searchReviewsQuery, r.config.reviewedLabel)
+	if err != nil {
+		return fmt.Errorf("failed to list reviewed reviews: %w", err)
+	}
+
+	log.Printf("Sweep pass: checking %d already-reviewed reviews for stale discussions\n", len(reviews))
+
+	for _, review := range reviews {
+		if r.store.IsReviewMuted(review.GetProjectID(), review.GetReviewID().ReviewID) {
+			continue
+		}
+		if err := r.sweepReview(review, botUserID); err != nil {
+			log.Printf("Sweep pass error in review %s: %v\n", review.GetBranch(), err)
+		}
+	}
+
+	return nil
+}
+
+func (r *replier) sweepReview(review *upsource.Review, botUserID string) error {
+	discussions, err := upsource.ListReviewDiscussions(r.ctx, r.upsourceClient, review)
+	if err != nil {
+		return fmt.Errorf("list discussions: %w", err)
+	}
+
+	reviewReplier := r.llmReplier.ForReview(review)
+
+	for _, d := range discussions {
+		last, ok := upsource.ShouldRecheckDiscussion(d, r.config.reviewedLabel, botUserID)
+		if !ok || r.config.escalation.Enabled && upsource.HasDiscussionLabel(d, r.config.escalation.Label) {
+			continue
+		}
+
+		result, err := reviewReplier.Recheck(d, botUserID)
+		if err != nil {
+			log.Printf("Failed to recheck discussion %s: %v\n", d.DiscussionID, err)
+			continue
+		}
+		if result == nil || !result.Close {
+			continue
+		}
+
+		if err := upsource.AddDiscussionComment(r.ctx, r.upsourceClient, review.GetProjectID(), d.DiscussionID, last.CommentID, result.Comment); err != nil {
+			log.Printf("Failed to post note in stale discussion %s: %v\n", d.DiscussionID, err)
+			continue
+		}
+		if err := upsource.ResolveDiscussion(r.ctx, r.upsourceClient, review.GetProjectID(), d.DiscussionID); err != nil {
+			log.Printf("Failed to resolve stale discussion %s: %v\n", d.DiscussionID, err)
+			continue
+		}
+		log.Printf("Resolved stale discussion %s (review %s)\n", d.DiscussionID, review.GetBranch())
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+func TestSweepDue(t *testing.T) {
+	r := &replier{config: &replierConfig{sweep: config.Sweep{Enabled: true, IntervalMinutes: 60}}}
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	if !r.sweepDue(start) {
+		t.Fatalf("first sweep should be due")
+	}
+	if r.sweepDue(start.Add(30 * time.Minute)) {
+		t.Fatalf("sweep should not be due before the interval has passed")
+	}
+	if !r.sweepDue(start.Add(time.Hour)) {
+		t.Fatalf("sweep should be due after the interval")
+	}
+
+	disabled := &replier{config: &replierConfig{}}
+	if disabled.sweepDue(start) {
+		t.Fatalf("disabled sweep should never be due")
+	}
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// Outcome is how developers responded to a comment of the bot.
+type Outcome string
+
+const (
+	// OutcomePending means the discussion is still going on.
+	OutcomePending Outcome = ""
+	// OutcomeAccepted means the developers agreed with the comment or fixed the code.
+	OutcomeAccepted Outcome = "accepted"
+	// OutcomeDisagreed means the developers rejected the comment.
+	OutcomeDisagreed Outcome = "disagreed"
+	// OutcomeIgnored means nobody responded to the comment.
+	OutcomeIgnored Outcome = "ignored"
+	// OutcomeResolvedWithoutReply means the discussion was resolved without a reply from a developer.
+	OutcomeResolvedWithoutReply Outcome = "resolved_without_reply"
+)
+
+// Outcomes lists the final outcomes in report order.
+var Outcomes = []Outcome{OutcomeAccepted, OutcomeDisagreed, OutcomeIgnored, OutcomeResolvedWithoutReply}
+
+// Feedback is a comment posted by the bot and how developers responded to it.
+type Feedback struct {
+	DiscussionID string `json:"discussionId"`
+	ProjectID    string `json:"projectId"`
+	ReviewID     string `json:"reviewId"`
+	// FileType is the extension of the commented file, or its name when it has none.
+	FileType string `json:"fileType"`
+	Severity string `json:"severity"`
+	// Model is the provider and model that wrote the comment, e.g. "openai/gpt-5-mini".
+	Model string `json:"model"`
+	// PromptVersion identifies the review prompt templates the comment was written with.
+	PromptVersion string    `json:"promptVersion,omitempty"`
+	PostedAt      time.Time `json:"postedAt"`
+	Outcome       Outcome   `json:"outcome,omitempty"`
+	ClassifiedAt  time.Time `json:"classifiedAt,omitzero"`
+}
+
+// AddFeedback stores a posted comment with a pending outcome.
+func (s *Store) AddFeedback(f Feedback) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.PostedAt.IsZero() {
+		f.PostedAt = time.Now().UTC()
+	}
+	s.state.Feedback = append(s.state.Feedback, f)
+
+	return s.save()
+}
+
+// PendingFeedback returns the comments of a review whose outcome is not known yet.
+func (s *Store) PendingFeedback(projectID, reviewID string) []Feedback {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Feedback
+	for _, f := range s.state.Feedback {
+		if f.ProjectID == projectID && f.ReviewID == reviewID && f.Outcome == OutcomePending {
+			out = append(out, f)
+		}
+	}
+
+	return out
+}
+
+// SetOutcome records the outcome of the comment that started a discussion.
+func (s *Store) SetOutcome(discussionID string, outcome Outcome) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.state.Feedback {
+		if s.state.Feedback[i].DiscussionID == discussionID {
+			s.state.Feedback[i].Outcome = outcome
+			s.state.Feedback[i].ClassifiedAt = time.Now().UTC()
+		}
+	}
+
+	return s.save()
+}
+
+// AllFeedback returns every stored comment.
+func (s *Store) AllFeedback() []Feedback {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Feedback(nil), s.state.Feedback...)
+}
+
+// OutcomeSummary counts the outcomes of a group of comments.
+type OutcomeSummary struct {
+	Key     string
+	Pending int
+	Counts  map[Outcome]int
+}
+
+// Classified returns the number of comments with a final outcome.
+func (s OutcomeSummary) Classified() int {
+	var n int
+	for _, count := range s.Counts {
+		n += count
+	}
+
+	return n
+}
+
+// AcceptanceRate is the share of accepted comments among the classified ones, 0 when there are none.
+func (s OutcomeSummary) AcceptanceRate() float64 {
+	classified := s.Classified()
+	if classified == 0 {
+		return 0
+	}
+
+	return float64(s.Counts[OutcomeAccepted]) / float64(classified)
+}
+
+// Summarize groups comments by key and counts their outcomes. Groups are sorted by key.
+func Summarize(feedback []Feedback, key func(Feedback) string) []OutcomeSummary {
+	byKey := make(map[string]*OutcomeSummary)
+	for _, f := range feedback {
+		k := key(f)
+		summary, ok := byKey[k]
+		if !ok {
+			summary = &OutcomeSummary{Key: k, Counts: make(map[Outcome]int)}
+			byKey[k] = summary
+		}
+
+		if f.Outcome == OutcomePending {
+			summary.Pending++
+		} else {
+			summary.Counts[f.Outcome]++
+		}
+	}
+
+	out := make([]OutcomeSummary, 0, len(byKey))
+	for _, summary := range byKey {
+		out = append(out, *summary)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+
+	return out
+}
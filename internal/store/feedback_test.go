@@ -0,0 +1,38 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.AddFeedback(Feedback{DiscussionID: "d1", ProjectID: "proj", ReviewID: "R-1", FileType: "go", Severity: "high", Model: "openai/gpt"}))
+	require.NoError(t, s.AddFeedback(Feedback{DiscussionID: "d2", ProjectID: "proj", ReviewID: "R-1", FileType: "go", Severity: "low", Model: "openai/gpt"}))
+	require.NoError(t, s.AddFeedback(Feedback{DiscussionID: "d3", ProjectID: "proj", ReviewID: "R-2", FileType: "sql", Severity: "low", Model: "gemini/flash"}))
+	require.NoError(t, s.SetOutcome("d1", OutcomeAccepted))
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+
+	pending := reopened.PendingFeedback("proj", "R-1")
+	require.Len(t, pending, 1)
+	require.Equal(t, "d2", pending[0].DiscussionID)
+
+	require.NoError(t, reopened.SetOutcome("d2", OutcomeDisagreed))
+	summaries := Summarize(reopened.AllFeedback(), func(f Feedback) string { return f.Model })
+	require.Len(t, summaries, 2)
+
+	require.Equal(t, "gemini/flash", summaries[0].Key)
+	require.Equal(t, 1, summaries[0].Pending)
+	require.Zero(t, summaries[0].AcceptanceRate())
+
+	require.Equal(t, "openai/gpt", summaries[1].Key)
+	require.Equal(t, 2, summaries[1].Classified())
+	require.InDelta(t, 0.5, summaries[1].AcceptanceRate(), 1e-9)
+}
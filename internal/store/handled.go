@@ -0,0 +1,87 @@
+package store
+
+import "slices"
+
+// handledReview holds the mention and command comments of a review the bot has handled.
+type handledReview struct {
+	ProjectID  string   `json:"projectId"`
+	ReviewID   string   `json:"reviewId"`
+	CommentIDs []string `json:"commentIds"`
+}
+
+// IsCommentHandled reports whether the bot has handled a mention or command comment of a review.
+func (s *Store) IsCommentHandled(projectID, reviewID, commentID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Contains(s.state.HandledComments[reviewKey(projectID, reviewID)].CommentIDs, commentID)
+}
+
+// MarkCommentHandled records a mention or command comment of a review as handled.
+func (s *Store) MarkCommentHandled(projectID, reviewID, commentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := reviewKey(projectID, reviewID)
+	handled := s.state.HandledComments[key]
+	if slices.Contains(handled.CommentIDs, commentID) {
+		return nil
+	}
+
+	if s.state.HandledComments == nil {
+		s.state.HandledComments = make(map[string]handledReview)
+	}
+	handled.ProjectID, handled.ReviewID = projectID, reviewID
+	handled.CommentIDs = append(handled.CommentIDs, commentID)
+	s.state.HandledComments[key] = handled
+
+	return s.save()
+}
+
+// PruneHandledComments forgets the handled comments of a review that are not among commentIDs,
+// the comments the review still has.
+func (s *Store) PruneHandledComments(projectID, reviewID string, commentIDs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := reviewKey(projectID, reviewID)
+	handled, ok := s.state.HandledComments[key]
+	if !ok {
+		return nil
+	}
+
+	kept := slices.DeleteFunc(slices.Clone(handled.CommentIDs), func(id string) bool {
+		return !slices.Contains(commentIDs, id)
+	})
+	if len(kept) == len(handled.CommentIDs) {
+		return nil
+	}
+
+	if len(kept) == 0 {
+		delete(s.state.HandledComments, key)
+	} else {
+		handled.CommentIDs = kept
+		s.state.HandledComments[key] = handled
+	}
+
+	return s.save()
+}
+
+// PruneHandledReviews forgets the handled comments of the reviews that are no longer open.
+func (s *Store) PruneHandledReviews(isOpen func(projectID, reviewID string) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changed bool
+	for key, handled := range s.state.HandledComments {
+		if !isOpen(handled.ProjectID, handled.ReviewID) {
+			delete(s.state.HandledComments, key)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	return s.save()
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandledComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := Open(path)
+	require.NoError(t, err)
+	require.False(t, s.IsCommentHandled("p", "r1", "c1"))
+
+	require.NoError(t, s.MarkCommentHandled("p", "r1", "c1"))
+	require.NoError(t, s.MarkCommentHandled("p", "r1", "c2"))
+	require.NoError(t, s.MarkCommentHandled("p", "r2", "c3"))
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	require.True(t, reopened.IsCommentHandled("p", "r1", "c1"))
+	require.False(t, reopened.IsCommentHandled("p", "r2", "c1"))
+
+	require.NoError(t, reopened.PruneHandledComments("p", "r1", []string{"c2", "c4"}))
+	require.False(t, reopened.IsCommentHandled("p", "r1", "c1"))
+	require.True(t, reopened.IsCommentHandled("p", "r1", "c2"))
+
+	require.NoError(t, reopened.PruneHandledReviews(func(projectID, reviewID string) bool { return reviewID == "r1" }))
+	require.True(t, reopened.IsCommentHandled("p", "r1", "c2"))
+	require.False(t, reopened.IsCommentHandled("p", "r2", "c3"))
+}
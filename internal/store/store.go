@@ -0,0 +1,207 @@
+// Package store persists the state the bot keeps between runs, such as muted reviews, suppressed findings,
+// the outcomes of posted comments, the summaries of long threads and the handled mentions and commands, in a JSON file.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Suppression is a kind of finding the bot must not report again.
+type Suppression struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"projectId"`
+	// Path limits the suppression to a file; empty means the whole project.
+	Path      string    `json:"path,omitempty"`
+	Text      string    `json:"text"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Learned suppressions are collected from rejected comments instead of being requested with a command.
+	Learned bool `json:"learned,omitempty"`
+	// Rejections holds the IDs of the discussions in which developers rejected the finding.
+	Rejections []string `json:"rejections,omitempty"`
+}
+
+// Matches reports whether the suppression applies to a file of the project.
+func (s Suppression) Matches(projectID, path string) bool {
+	return s.ProjectID == projectID && (s.Path == "" || s.Path == path)
+}
+
+// Active reports whether the suppression is in effect. Learned suppressions need minRejections rejections.
+func (s Suppression) Active(minRejections int) bool {
+	return !s.Learned || len(s.Rejections) >= minRejections
+}
+
+type state struct {
+	// MutedReviews holds "projectID/reviewID" keys of the reviews the bot must stay quiet in.
+	MutedReviews map[string]bool `json:"mutedReviews,omitempty"`
+	Suppressions []Suppression   `json:"suppressions,omitempty"`
+	Feedback     []Feedback      `json:"feedback,omitempty"`
+	// ThreadSummaries holds the summaries of long discussion threads by discussion ID.
+	ThreadSummaries map[string]ThreadSummary `json:"threadSummaries,omitempty"`
+	// HandledComments holds the mention and command comments the bot has handled, by "projectID/reviewID" key.
+	HandledComments map[string]handledReview `json:"handledComments,omitempty"`
+}
+
+// Store is a JSON file backed state store. It is safe for concurrent use.
+type Store struct {
+	path  string
+	mu    sync.Mutex
+	state state
+}
+
+// Open loads the store from path. A missing file yields an empty store; the file is created on the first change.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return nil, fmt.Errorf("failed to parse store %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// save writes the state to a temporary file and renames it over the store, so a crash never leaves a partial file.
+// The caller must hold s.mu.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary store file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace store %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func reviewKey(projectID, reviewID string) string {
+	return projectID + "/" + reviewID
+}
+
+// SetReviewMuted mutes or unmutes the bot in a review.
+func (s *Store) SetReviewMuted(projectID, reviewID string, muted bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := reviewKey(projectID, reviewID)
+	if s.state.MutedReviews[key] == muted {
+		return nil
+	}
+
+	if muted {
+		if s.state.MutedReviews == nil {
+			s.state.MutedReviews = make(map[string]bool)
+		}
+		s.state.MutedReviews[key] = true
+	} else {
+		delete(s.state.MutedReviews, key)
+	}
+
+	return s.save()
+}
+
+// IsReviewMuted reports whether the bot is muted in a review.
+func (s *Store) IsReviewMuted(projectID, reviewID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state.MutedReviews[reviewKey(projectID, reviewID)]
+}
+
+// AddSuppression stores a suppression, assigning its ID and creation time when they are empty.
+func (s *Store) AddSuppression(sup Suppression) (Suppression, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sup.ID == "" {
+		sup.ID = fmt.Sprintf("s%d", time.Now().UnixNano())
+	}
+	if sup.CreatedAt.IsZero() {
+		sup.CreatedAt = time.Now().UTC()
+	}
+	s.state.Suppressions = append(s.state.Suppressions, sup)
+
+	return sup, s.save()
+}
+
+// Suppressions returns the suppressions that apply to a file of the project.
+func (s *Store) Suppressions(projectID, path string) []Suppression {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Suppression
+	for _, sup := range s.state.Suppressions {
+		if sup.Matches(projectID, path) {
+			out = append(out, sup)
+		}
+	}
+
+	return out
+}
+
+// AllSuppressions returns every stored suppression.
+func (s *Store) AllSuppressions() []Suppression {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Suppression(nil), s.state.Suppressions...)
+}
+
+// UpdateSuppression replaces the stored suppression with the same ID.
+func (s *Store) UpdateSuppression(sup Suppression) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.state.Suppressions {
+		if s.state.Suppressions[i].ID == sup.ID {
+			s.state.Suppressions[i] = sup
+			return s.save()
+		}
+	}
+
+	return fmt.Errorf("suppression %s not found", sup.ID)
+}
+
+// DeleteSuppression removes a suppression and reports whether it existed.
+func (s *Store) DeleteSuppression(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.state.Suppressions {
+		if s.state.Suppressions[i].ID == id {
+			s.state.Suppressions = append(s.state.Suppressions[:i], s.state.Suppressions[i+1:]...)
+			return true, s.save()
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorePersistsState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := Open(path)
+	require.NoError(t, err)
+	require.False(t, s.IsReviewMuted("proj", "R-1"))
+
+	require.NoError(t, s.SetReviewMuted("proj", "R-1", true))
+	sup, err := s.AddSuppression(Suppression{ProjectID: "proj", Path: "a.go", Text: "Close errors are ignored on purpose"})
+	require.NoError(t, err)
+	require.NotEmpty(t, sup.ID)
+	_, err = s.AddSuppression(Suppression{ProjectID: "proj", Text: "Project wide"})
+	require.NoError(t, err)
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	require.True(t, reopened.IsReviewMuted("proj", "R-1"))
+	require.False(t, reopened.IsReviewMuted("other", "R-1"))
+	require.Len(t, reopened.Suppressions("proj", "a.go"), 2)
+	require.Len(t, reopened.Suppressions("proj", "b.go"), 1)
+	require.Empty(t, reopened.Suppressions("other", "a.go"))
+
+	require.NoError(t, reopened.SetReviewMuted("proj", "R-1", false))
+	reopened, err = Open(path)
+	require.NoError(t, err)
+	require.False(t, reopened.IsReviewMuted("proj", "R-1"))
+}
+
+func TestUpdateAndDeleteSuppression(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(t, err)
+
+	sup, err := s.AddSuppression(Suppression{ProjectID: "proj", Text: "Close errors are ignored", Learned: true, Rejections: []string{"d1"}})
+	require.NoError(t, err)
+	require.False(t, sup.Active(2))
+
+	sup.Rejections = append(sup.Rejections, "d2")
+	require.NoError(t, s.UpdateSuppression(sup))
+	require.True(t, s.AllSuppressions()[0].Active(2))
+	require.Error(t, s.UpdateSuppression(Suppression{ID: "missing"}))
+
+	deleted, err := s.DeleteSuppression(sup.ID)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	require.Empty(t, s.AllSuppressions())
+
+	deleted, err = s.DeleteSuppression(sup.ID)
+	require.NoError(t, err)
+	require.False(t, deleted)
+}
+
+func TestOpenFailsForInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	_, err := Open(path)
+	require.ErrorContains(t, err, "failed to parse store")
+}
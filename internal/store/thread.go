@@ -0,0 +1,39 @@
+package store
+
+import "time"
+
+// ThreadSummary is the summary of the older messages of a long discussion thread.
+type ThreadSummary struct {
+	DiscussionID string `json:"discussionId"`
+	// Covered is the number of oldest comments of the thread the summary replaces.
+	Covered int `json:"covered"`
+	// LastCommentID is the ID of the last covered comment, used to detect threads that changed since.
+	LastCommentID string    `json:"lastCommentId"`
+	Text          string    `json:"text"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ThreadSummary returns the stored summary of a discussion thread.
+func (s *Store) ThreadSummary(discussionID string) (ThreadSummary, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summary, ok := s.state.ThreadSummaries[discussionID]
+	return summary, ok
+}
+
+// SetThreadSummary stores the summary of a discussion thread, replacing the previous one.
+func (s *Store) SetThreadSummary(summary ThreadSummary) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if summary.UpdatedAt.IsZero() {
+		summary.UpdatedAt = time.Now().UTC()
+	}
+	if s.state.ThreadSummaries == nil {
+		s.state.ThreadSummaries = make(map[string]ThreadSummary)
+	}
+	s.state.ThreadSummaries[summary.DiscussionID] = summary
+
+	return s.save()
+}
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := Open(path)
+	require.NoError(t, err)
+	_, ok := s.ThreadSummary("d1")
+	require.False(t, ok)
+
+	require.NoError(t, s.SetThreadSummary(ThreadSummary{DiscussionID: "d1", Covered: 3, LastCommentID: "c3", Text: "Old summary"}))
+	require.NoError(t, s.SetThreadSummary(ThreadSummary{DiscussionID: "d1", Covered: 5, LastCommentID: "c5", Text: "New summary"}))
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	summary, ok := reopened.ThreadSummary("d1")
+	require.True(t, ok)
+	require.Equal(t, 5, summary.Covered)
+	require.Equal(t, "c5", summary.LastCommentID)
+	require.Equal(t, "New summary", summary.Text)
+	require.False(t, summary.UpdatedAt.IsZero())
+}
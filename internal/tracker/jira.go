@@ -0,0 +1,71 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Jira fetches issues with the Jira REST API v2.
+type Jira struct {
+	httpClient              *http.Client
+	baseURL                 string
+	email                   string
+	token                   string
+	acceptanceCriteriaField string
+}
+
+// NewJira creates a Jira client. With an email it uses basic authentication with token as the API token
+// (Jira Cloud), otherwise token is sent as a personal access token (Jira Server and Data Center).
+func NewJira(httpClient *http.Client, baseURL, email, token, acceptanceCriteriaField string) *Jira {
+	return &Jira{
+		httpClient:              httpClient,
+		baseURL:                 baseURL,
+		email:                   email,
+		token:                   token,
+		acceptanceCriteriaField: acceptanceCriteriaField,
+	}
+}
+
+type jiraIssue struct {
+	Key    string                     `json:"key"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+func (j *Jira) GetIssue(ctx context.Context, key string) (*Issue, error) {
+	fields := []string{"summary", "description"}
+	if j.acceptanceCriteriaField != "" {
+		fields = append(fields, j.acceptanceCriteriaField)
+	}
+	u := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=%s", j.baseURL, url.PathEscape(key), url.QueryEscape(strings.Join(fields, ",")))
+
+	var issue jiraIssue
+	if err := getJSON(ctx, j.httpClient, u, j.authorize, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get Jira issue %s: %w", key, err)
+	}
+
+	result := &Issue{
+		Key:         issue.Key,
+		Summary:     fieldText(issue.Fields["summary"]),
+		Description: fieldText(issue.Fields["description"]),
+	}
+	if result.Key == "" {
+		result.Key = key
+	}
+	if j.acceptanceCriteriaField != "" {
+		result.AcceptanceCriteria = fieldText(issue.Fields[j.acceptanceCriteriaField])
+	}
+
+	return result, nil
+}
+
+func (j *Jira) authorize(r *http.Request) {
+	if j.email != "" {
+		r.SetBasicAuth(j.email, j.token)
+		return
+	}
+	r.Header.Set("Authorization", "Bearer "+j.token)
+}
@@ -0,0 +1,103 @@
+// Package tracker fetches the issues mentioned in a review from YouTrack or Jira,
+// so that the review prompt can include the requirements behind the change.
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/config"
+)
+
+// ErrNotFound is returned when the issue does not exist or is not visible to the configured user.
+var ErrNotFound = errors.New("issue not found")
+
+// Issue is the part of a tracker issue that describes the requirements.
+type Issue struct {
+	Key                string
+	Summary            string
+	Description        string
+	AcceptanceCriteria string
+}
+
+// Client fetches issues by their key, e.g. "PROJ-123".
+type Client interface {
+	GetIssue(ctx context.Context, key string) (*Issue, error)
+}
+
+const requestTimeout = 10 * time.Second
+
+// New creates the client for the configured tracker type.
+func New(cfg config.Tracker) (Client, error) {
+	httpClient := &http.Client{Timeout: requestTimeout}
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+
+	switch cfg.Type {
+	case config.TrackerTypeYouTrack:
+		return NewYouTrack(httpClient, baseURL, cfg.Token, cfg.AcceptanceCriteriaField), nil
+	case config.TrackerTypeJira:
+		return NewJira(httpClient, baseURL, cfg.Email, cfg.Token, cfg.AcceptanceCriteriaField), nil
+	default:
+		return nil, fmt.Errorf("unknown tracker type %q", cfg.Type)
+	}
+}
+
+// Fetch fetches up to maxIssues of the issues by key. Issues that cannot be fetched are logged and skipped.
+func Fetch(ctx context.Context, client Client, keys []string, maxIssues int) []*Issue {
+	var issues []*Issue
+	for _, key := range keys {
+		if len(issues) >= maxIssues {
+			break
+		}
+
+		issue, err := client.GetIssue(ctx, key)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				log.Printf("Failed to fetch issue %s: %v\n", key, err)
+			}
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// Format renders the issues as a prompt section. The text of each issue is cut to maxLength characters.
+func Format(issues []*Issue, maxLength int) string {
+	if len(issues) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n### Linked issues:\n\n")
+	b.WriteString("Check that the change implements the requirements below and point out anything missing or contradicting them.\n")
+	for _, issue := range issues {
+		var text strings.Builder
+		if d := strings.TrimSpace(issue.Description); d != "" {
+			_, _ = fmt.Fprintf(&text, "%s\n", d)
+		}
+		if ac := strings.TrimSpace(issue.AcceptanceCriteria); ac != "" {
+			_, _ = fmt.Fprintf(&text, "\nAcceptance criteria:\n%s\n", ac)
+		}
+
+		_, _ = fmt.Fprintf(&b, "\n#### %s: %s\n\n%s", issue.Key, issue.Summary, truncate(text.String(), maxLength))
+	}
+
+	return b.String()
+}
+
+// truncate cuts text to at most maxLength characters, marking the cut.
+func truncate(text string, maxLength int) string {
+	runes := []rune(text)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return text
+	}
+
+	return string(runes[:maxLength]) + "…\n"
+}
@@ -0,0 +1,138 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestYouTrackGetIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer yt-token", r.Header.Get("Authorization"))
+		if r.URL.Path != "/api/issues/PROJ-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.Contains(t, r.URL.Query().Get("fields"), "customFields")
+		_, _ = fmt.Fprint(w, `{
+			"idReadable": "PROJ-1",
+			"summary": "Retry uploads",
+			"description": "Uploads fail on flaky networks.",
+			"customFields": [
+				{"name": "Priority", "value": {"name": "Major"}},
+				{"name": "Acceptance Criteria", "value": {"text": "Three retries with backoff."}}
+			]
+		}`)
+	}))
+	defer server.Close()
+
+	client := NewYouTrack(server.Client(), server.URL, "yt-token", "Acceptance Criteria")
+
+	issue, err := client.GetIssue(context.Background(), "PROJ-1")
+	require.NoError(t, err)
+	require.Equal(t, &Issue{
+		Key:                "PROJ-1",
+		Summary:            "Retry uploads",
+		Description:        "Uploads fail on flaky networks.",
+		AcceptanceCriteria: "Three retries with backoff.",
+	}, issue)
+
+	_, err = client.GetIssue(context.Background(), "PROJ-2")
+	require.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestJiraGetIssue(t *testing.T) {
+	handler := func(t *testing.T, checkAuth func(r *http.Request)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			checkAuth(r)
+			require.Equal(t, "/rest/api/2/issue/OPS-7", r.URL.Path)
+			require.Equal(t, "summary,description,customfield_10042", r.URL.Query().Get("fields"))
+			_, _ = fmt.Fprint(w, `{
+				"key": "OPS-7",
+				"fields": {
+					"summary": "Rotate keys",
+					"description": null,
+					"customfield_10042": "Old keys stop working."
+				}
+			}`)
+		}
+	}
+	want := &Issue{Key: "OPS-7", Summary: "Rotate keys", AcceptanceCriteria: "Old keys stop working."}
+
+	t.Run("basic auth with email", func(t *testing.T) {
+		server := httptest.NewServer(handler(t, func(r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			require.True(t, ok)
+			require.Equal(t, "bot@example.com", user)
+			require.Equal(t, "api-token", password)
+		}))
+		defer server.Close()
+
+		issue, err := NewJira(server.Client(), server.URL, "bot@example.com", "api-token", "customfield_10042").GetIssue(context.Background(), "OPS-7")
+		require.NoError(t, err)
+		require.Equal(t, want, issue)
+	})
+
+	t.Run("personal access token", func(t *testing.T) {
+		server := httptest.NewServer(handler(t, func(r *http.Request) {
+			require.Equal(t, "Bearer pat", r.Header.Get("Authorization"))
+		}))
+		defer server.Close()
+
+		issue, err := NewJira(server.Client(), server.URL, "", "pat", "customfield_10042").GetIssue(context.Background(), "OPS-7")
+		require.NoError(t, err)
+		require.Equal(t, want, issue)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, "unauthorized")
+		}))
+		defer server.Close()
+
+		_, err := NewJira(server.Client(), server.URL, "", "pat", "").GetIssue(context.Background(), "OPS-7")
+		require.EqualError(t, err, "failed to get Jira issue OPS-7: unexpected status 401: unauthorized")
+	})
+}
+
+type fakeClient map[string]*Issue
+
+func (f fakeClient) GetIssue(_ context.Context, key string) (*Issue, error) {
+	if issue, ok := f[key]; ok {
+		return issue, nil
+	}
+	return nil, ErrNotFound
+}
+
+func TestFetch(t *testing.T) {
+	client := fakeClient{
+		"A-1": {Key: "A-1"},
+		"B-2": {Key: "B-2"},
+		"C-3": {Key: "C-3"},
+	}
+
+	issues := Fetch(context.Background(), client, []string{"UTF-8", "A-1", "B-2", "C-3"}, 2)
+	require.Equal(t, []*Issue{{Key: "A-1"}, {Key: "B-2"}}, issues)
+}
+
+func TestFormat(t *testing.T) {
+	require.Empty(t, Format(nil, 100))
+
+	got := Format([]*Issue{
+		{Key: "A-1", Summary: "Short", Description: "Do it.", AcceptanceCriteria: "Done."},
+		{Key: "B-2", Summary: "Long", Description: "0123456789"},
+	}, 5)
+	require.Equal(t, "\n\n### Linked issues:\n\n"+
+		"Check that the change implements the requirements below and point out anything missing or contradicting them.\n"+
+		"\n#### A-1: Short\n\nDo it…\n"+
+		"\n#### B-2: Long\n\n01234…\n", got)
+
+	got = Format([]*Issue{{Key: "A-1", Summary: "Short", Description: "Do it.", AcceptanceCriteria: "Done."}}, 0)
+	require.Contains(t, got, "Do it.\n\nAcceptance criteria:\nDone.\n")
+}
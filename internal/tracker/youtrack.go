@@ -0,0 +1,67 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// YouTrack fetches issues with the YouTrack REST API.
+type YouTrack struct {
+	httpClient              *http.Client
+	baseURL                 string
+	token                   string
+	acceptanceCriteriaField string
+}
+
+// NewYouTrack creates a YouTrack client authorized with a permanent token.
+func NewYouTrack(httpClient *http.Client, baseURL, token, acceptanceCriteriaField string) *YouTrack {
+	return &YouTrack{
+		httpClient:              httpClient,
+		baseURL:                 baseURL,
+		token:                   token,
+		acceptanceCriteriaField: acceptanceCriteriaField,
+	}
+}
+
+type youTrackIssue struct {
+	IDReadable   string `json:"idReadable"`
+	Summary      string `json:"summary"`
+	Description  string `json:"description"`
+	CustomFields []struct {
+		Name  string          `json:"name"`
+		Value json.RawMessage `json:"value"`
+	} `json:"customFields"`
+}
+
+func (y *YouTrack) GetIssue(ctx context.Context, key string) (*Issue, error) {
+	u := fmt.Sprintf("%s/api/issues/%s?fields=%s", y.baseURL, url.PathEscape(key),
+		url.QueryEscape("idReadable,summary,description,customFields(name,value(text,name))"))
+
+	var issue youTrackIssue
+	if err := getJSON(ctx, y.httpClient, u, y.authorize, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get YouTrack issue %s: %w", key, err)
+	}
+
+	result := &Issue{
+		Key:         issue.IDReadable,
+		Summary:     issue.Summary,
+		Description: issue.Description,
+	}
+	if result.Key == "" {
+		result.Key = key
+	}
+	for _, f := range issue.CustomFields {
+		if y.acceptanceCriteriaField != "" && f.Name == y.acceptanceCriteriaField {
+			result.AcceptanceCriteria = fieldText(f.Value)
+		}
+	}
+
+	return result, nil
+}
+
+func (y *YouTrack) authorize(r *http.Request) {
+	r.Header.Set("Authorization", "Bearer "+y.token)
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	AnalyzerModeLLM  = "llm"
+	AnalyzerModePost = "post"
+)
+
+const (
+	AnalyzerFormatText         = "text"
+	AnalyzerFormatGolangciLint = "golangci-lint"
+	AnalyzerFormatStaticcheck  = "staticcheck"
+	AnalyzerFormatSemgrep      = "semgrep"
+)
+
+// Analyzers configures the static analysis stage that runs before the LLM review.
+type Analyzers struct {
+	Enabled bool `yaml:"enabled"`
+	// Mode selects what happens with findings on changed lines:
+	// "llm" feeds them into the review prompt, "post" posts them as discussions directly.
+	Mode string `yaml:"mode"`
+	// CheckoutDir is the parent directory for temporary checkouts of review branches.
+	CheckoutDir string         `yaml:"checkoutDir"`
+	Timeout     time.Duration  `yaml:"timeout"`
+	MaxFindings int            `yaml:"maxFindings"`
+	Tools       []AnalyzerTool `yaml:"tools"`
+}
+
+type AnalyzerTool struct {
+	Name    string `yaml:"name"`
+	Command string `yaml:"command"`
+	Format  string `yaml:"format"`
+	// Severity is used for findings whose output format carries no severity.
+	Severity string `yaml:"severity"`
+}
+
+func (a *Analyzers) Validate() error {
+	if !a.Enabled {
+		return nil
+	}
+
+	if a.Mode == "" {
+		a.Mode = AnalyzerModeLLM
+	}
+	if a.Mode != AnalyzerModeLLM && a.Mode != AnalyzerModePost {
+		return fmt.Errorf("analyzers.mode must be %q or %q", AnalyzerModeLLM, AnalyzerModePost)
+	}
+
+	if len(a.Tools) == 0 {
+		return fmt.Errorf("analyzers.tools must not be empty when analyzers.enabled is true")
+	}
+
+	for i := range a.Tools {
+		tool := &a.Tools[i]
+		if strings.TrimSpace(tool.Name) == "" {
+			return fmt.Errorf("analyzers.tools[%d].name is required", i)
+		}
+		if strings.TrimSpace(tool.Command) == "" {
+			return fmt.Errorf("analyzers.tools[%d].command is required", i)
+		}
+		switch tool.Format {
+		case AnalyzerFormatText, AnalyzerFormatGolangciLint, AnalyzerFormatStaticcheck, AnalyzerFormatSemgrep:
+		default:
+			return fmt.Errorf("analyzers.tools[%d].format %q is not supported", i, tool.Format)
+		}
+		switch tool.Severity {
+		case "":
+			tool.Severity = "medium"
+		case "low", "medium", "high":
+		default:
+			return fmt.Errorf("analyzers.tools[%d].severity must be one of low, medium or high", i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzersValidate(t *testing.T) {
+	t.Run("skips validation when disabled", func(t *testing.T) {
+		a := &Analyzers{Mode: "bogus"}
+		require.NoError(t, a.Validate())
+	})
+
+	t.Run("sets defaults", func(t *testing.T) {
+		a := validAnalyzers()
+		a.Mode = ""
+		a.Tools[0].Severity = ""
+
+		require.NoError(t, a.Validate())
+		require.Equal(t, AnalyzerModeLLM, a.Mode)
+		require.Equal(t, "medium", a.Tools[0].Severity)
+	})
+
+	testCases := []struct {
+		name        string
+		mutate      func(*Analyzers)
+		expectedErr string
+	}{
+		{
+			name:        "unknown mode",
+			mutate:      func(a *Analyzers) { a.Mode = "email" },
+			expectedErr: `analyzers.mode must be "llm" or "post"`,
+		},
+		{
+			name:        "no tools",
+			mutate:      func(a *Analyzers) { a.Tools = nil },
+			expectedErr: "analyzers.tools must not be empty when analyzers.enabled is true",
+		},
+		{
+			name:        "missing tool command",
+			mutate:      func(a *Analyzers) { a.Tools[0].Command = " " },
+			expectedErr: "analyzers.tools[0].command is required",
+		},
+		{
+			name:        "unsupported format",
+			mutate:      func(a *Analyzers) { a.Tools[0].Format = "sarif" },
+			expectedErr: `analyzers.tools[0].format "sarif" is not supported`,
+		},
+		{
+			name:        "unknown severity",
+			mutate:      func(a *Analyzers) { a.Tools[0].Severity = "hgih" },
+			expectedErr: "analyzers.tools[0].severity must be one of low, medium or high",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := validAnalyzers()
+			tc.mutate(a)
+			require.EqualError(t, a.Validate(), tc.expectedErr)
+		})
+	}
+}
+
+func validAnalyzers() *Analyzers {
+	return &Analyzers{
+		Enabled: true,
+		Mode:    AnalyzerModePost,
+		Tools: []AnalyzerTool{
+			{Name: "go vet", Command: "go vet ./...", Format: AnalyzerFormatText, Severity: "low"},
+		},
+	}
+}
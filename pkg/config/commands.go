@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultCommandPrefix = "/ai"
+
+// Commands configures the slash commands developers can post in discussions, e.g. "/ai explain".
+type Commands struct {
+	Enabled bool `yaml:"enabled"`
+	// Prefix starts every command.
+	Prefix string `yaml:"prefix"`
+}
+
+func (c *Commands) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Prefix == "" {
+		c.Prefix = defaultCommandPrefix
+	}
+	if strings.ContainsAny(c.Prefix, " \t\n") {
+		return fmt.Errorf("replies.commands.prefix must not contain whitespace")
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandsValidate(t *testing.T) {
+	t.Run("defaults prefix", func(t *testing.T) {
+		c := &Commands{Enabled: true}
+		require.NoError(t, c.Validate())
+		require.Equal(t, "/ai", c.Prefix)
+	})
+
+	t.Run("fails for prefix with whitespace", func(t *testing.T) {
+		c := &Commands{Enabled: true, Prefix: "/ai bot"}
+		require.EqualError(t, c.Validate(), "replies.commands.prefix must not contain whitespace")
+	})
+}
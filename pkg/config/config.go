@@ -5,16 +5,25 @@ import (
 	"os"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
 )
 
 type Config struct {
-	Upsource  Upsource  `yaml:"upsource"`
-	Gitlab    Gitlab    `yaml:"gitlab"`
-	Review    Review    `yaml:"review"`
-	Providers Providers `yaml:"providers"`
-	Polling   Polling   `yaml:"polling"`
-	Replies   Replies   `yaml:"replies"`
-	Metrics   Metrics   `yaml:"metrics"`
+	Upsource     Upsource     `yaml:"upsource"`
+	Gitlab       Gitlab       `yaml:"gitlab"`
+	Review       Review       `yaml:"review"`
+	Providers    Providers    `yaml:"providers"`
+	Polling      Polling      `yaml:"polling"`
+	Replies      Replies      `yaml:"replies"`
+	Metrics      Metrics      `yaml:"metrics"`
+	Analyzers    Analyzers    `yaml:"analyzers"`
+	Redaction    Redaction    `yaml:"redaction"`
+	Tracker      Tracker      `yaml:"tracker"`
+	Store        Store        `yaml:"store"`
+	Feedback     Feedback     `yaml:"feedback"`
+	Suppressions Suppressions `yaml:"suppressions"`
+	Style        Style        `yaml:"style"`
 }
 
 type Metrics struct {
@@ -24,9 +33,14 @@ type Metrics struct {
 }
 
 type Replies struct {
-	Enabled       bool   `yaml:"enabled"`
-	MaxPerThread  int    `yaml:"maxPerThread"`
-	SystemMessage string `yaml:"systemMessage"`
+	Enabled       bool       `yaml:"enabled"`
+	MaxPerThread  int        `yaml:"maxPerThread"`
+	SystemMessage string     `yaml:"systemMessage"`
+	Mentions      Mentions   `yaml:"mentions"`
+	Commands      Commands   `yaml:"commands"`
+	Sweep         Sweep      `yaml:"sweep"`
+	Memory        Memory     `yaml:"memory"`
+	Escalation    Escalation `yaml:"escalation"`
 }
 
 type Polling struct {
@@ -83,6 +97,26 @@ func ValidateConfig(config *Config) error {
 		return fmt.Errorf("review config is invalid: %w", err)
 	}
 
+	if err := config.Review.Critique.Validate(&config.Providers); err != nil {
+		return fmt.Errorf("review config is invalid: %w", err)
+	}
+
+	if err := config.Style.Validate(); err != nil {
+		return fmt.Errorf("style config is invalid: %w", err)
+	}
+
+	if err := config.Analyzers.Validate(); err != nil {
+		return fmt.Errorf("analyzers config is invalid: %w", err)
+	}
+
+	if err := config.Redaction.Validate(); err != nil {
+		return fmt.Errorf("redaction config is invalid: %w", err)
+	}
+
+	if err := config.Tracker.Validate(); err != nil {
+		return fmt.Errorf("tracker config is invalid: %w", err)
+	}
+
 	if config.Metrics.Enabled {
 		if config.Metrics.ListenAddress == "" {
 			config.Metrics.ListenAddress = ":2112"
@@ -99,6 +133,56 @@ func ValidateConfig(config *Config) error {
 		if config.Replies.SystemMessage == "" {
 			return fmt.Errorf("replies.systemMessage is required when replies.enabled is true")
 		}
+		if err := prompt.Validate("replies.systemMessage", config.Replies.SystemMessage); err != nil {
+			return fmt.Errorf("replies.systemMessage is not a valid template: %w", err)
+		}
+	}
+
+	if config.Replies.Mentions.Enabled && !config.Replies.Enabled {
+		return fmt.Errorf("replies.mentions.enabled requires replies.enabled")
+	}
+	if err := config.Replies.Mentions.Validate(); err != nil {
+		return fmt.Errorf("replies config is invalid: %w", err)
+	}
+
+	if config.Replies.Commands.Enabled && !config.Replies.Enabled {
+		return fmt.Errorf("replies.commands.enabled requires replies.enabled")
+	}
+	if err := config.Replies.Commands.Validate(); err != nil {
+		return fmt.Errorf("replies config is invalid: %w", err)
+	}
+
+	if config.Replies.Sweep.Enabled && !config.Replies.Enabled {
+		return fmt.Errorf("replies.sweep.enabled requires replies.enabled")
+	}
+	if err := config.Replies.Sweep.Validate(); err != nil {
+		return fmt.Errorf("replies config is invalid: %w", err)
+	}
+
+	if config.Replies.Memory.Enabled && !config.Replies.Enabled {
+		return fmt.Errorf("replies.memory.enabled requires replies.enabled")
+	}
+	if err := config.Replies.Memory.Validate(); err != nil {
+		return fmt.Errorf("replies config is invalid: %w", err)
+	}
+
+	if config.Replies.Escalation.Enabled && !config.Replies.Enabled {
+		return fmt.Errorf("replies.escalation.enabled requires replies.enabled")
+	}
+	if err := config.Replies.Escalation.Validate(); err != nil {
+		return fmt.Errorf("replies config is invalid: %w", err)
+	}
+
+	if err := config.Store.Validate(); err != nil {
+		return fmt.Errorf("store config is invalid: %w", err)
+	}
+
+	if err := config.Suppressions.Validate(); err != nil {
+		return fmt.Errorf("suppressions config is invalid: %w", err)
+	}
+
+	if err := config.Feedback.Validate(); err != nil {
+		return fmt.Errorf("feedback config is invalid: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,47 @@
+package config
+
+import "fmt"
+
+// Critique configures the optional second LLM pass that verifies draft review comments.
+type Critique struct {
+	Enabled bool `yaml:"enabled"`
+	// Provider selects one of the configured providers for the critique pass.
+	// Empty uses the same provider as the review.
+	Provider string `yaml:"provider"`
+	// Model overrides the model of the selected provider. The agent provider has no model to override.
+	Model string `yaml:"model"`
+	// MinConfidence drops comments the critique is less confident about (0..1).
+	MinConfidence float64 `yaml:"minConfidence"`
+	// SystemMessage overrides the built-in critique instructions.
+	SystemMessage string `yaml:"systemMessage"`
+}
+
+func (c *Critique) Validate(providers *Providers) error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.MinConfidence < 0 || c.MinConfidence > 1 {
+		return fmt.Errorf("review.critique.minConfidence must be between 0 and 1")
+	}
+
+	switch c.Provider {
+	case "":
+	case ProviderAgent, ProviderOpenAI, ProviderGemini, ProviderAnthropic:
+		if !providers.Enabled(c.Provider) {
+			return fmt.Errorf("review.critique.provider %q is not configured in providers", c.Provider)
+		}
+	default:
+		return fmt.Errorf("review.critique.provider %q is not supported", c.Provider)
+	}
+
+	provider := c.Provider
+	if provider == "" {
+		provider = providers.ActiveLLMProvider()
+	}
+	if c.Model != "" && provider == ProviderAgent {
+		return fmt.Errorf("review.critique.model is not supported by the %s provider", ProviderAgent)
+	}
+
+	return nil
+}
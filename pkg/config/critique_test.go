@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCritiqueValidate(t *testing.T) {
+	providers := &Providers{
+		OpenAI:    OpenAI{APIKey: "key", Model: "gpt-5-mini"},
+		Anthropic: Anthropic{APIKey: "key", Model: "claude-opus-4-1"},
+	}
+
+	t.Run("allows the review provider by default", func(t *testing.T) {
+		c := &Critique{Enabled: true, MinConfidence: 0.6}
+		require.NoError(t, c.Validate(providers))
+	})
+
+	t.Run("allows another configured provider", func(t *testing.T) {
+		c := &Critique{Enabled: true, Provider: ProviderAnthropic, Model: "claude-haiku-4-5"}
+		require.NoError(t, c.Validate(providers))
+	})
+
+	t.Run("fails for provider that is not configured", func(t *testing.T) {
+		c := &Critique{Enabled: true, Provider: ProviderGemini}
+		require.EqualError(t, c.Validate(providers), `review.critique.provider "gemini" is not configured in providers`)
+	})
+
+	t.Run("fails for unknown provider", func(t *testing.T) {
+		c := &Critique{Enabled: true, Provider: "mistral"}
+		require.EqualError(t, c.Validate(providers), `review.critique.provider "mistral" is not supported`)
+	})
+
+	t.Run("fails for model override of the agent provider", func(t *testing.T) {
+		agent := &Providers{Agent: Agent{Command: "agent"}}
+		c := &Critique{Enabled: true, Model: "gpt-5"}
+		require.EqualError(t, c.Validate(agent), "review.critique.model is not supported by the agent provider")
+	})
+
+	t.Run("fails for confidence out of range", func(t *testing.T) {
+		c := &Critique{Enabled: true, MinConfidence: 60}
+		require.EqualError(t, c.Validate(providers), "review.critique.minConfidence must be between 0 and 1")
+	})
+}
@@ -0,0 +1,49 @@
+package config
+
+import "fmt"
+
+const (
+	DedupModeSuppress = "suppress"
+	DedupModeReply    = "reply"
+)
+
+// Dedup configures the check that keeps the bot from posting comments that already exist in the review.
+type Dedup struct {
+	Enabled bool `yaml:"enabled"`
+	// Mode selects what happens with a duplicate: "suppress" drops it,
+	// "reply" adds it to the existing thread unless the bot already has the last word there.
+	Mode string `yaml:"mode"`
+	// LineWindow is how many lines apart two comments on the same file may be to count as duplicates.
+	LineWindow int `yaml:"lineWindow"`
+	// MinSimilarity is the minimal share of common words (0..1) for two comments to count as duplicates.
+	MinSimilarity float64 `yaml:"minSimilarity"`
+}
+
+func (d *Dedup) Validate() error {
+	if !d.Enabled {
+		return nil
+	}
+
+	if d.Mode == "" {
+		d.Mode = DedupModeSuppress
+	}
+	if d.Mode != DedupModeSuppress && d.Mode != DedupModeReply {
+		return fmt.Errorf("review.dedup.mode must be %q or %q", DedupModeSuppress, DedupModeReply)
+	}
+
+	if d.LineWindow < 0 {
+		return fmt.Errorf("review.dedup.lineWindow must not be negative")
+	}
+	if d.LineWindow == 0 {
+		d.LineWindow = 3
+	}
+
+	if d.MinSimilarity < 0 || d.MinSimilarity > 1 {
+		return fmt.Errorf("review.dedup.minSimilarity must be between 0 and 1")
+	}
+	if d.MinSimilarity == 0 {
+		d.MinSimilarity = 0.6
+	}
+
+	return nil
+}
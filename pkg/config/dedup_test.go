@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupValidate(t *testing.T) {
+	t.Run("sets defaults", func(t *testing.T) {
+		d := &Dedup{Enabled: true}
+		require.NoError(t, d.Validate())
+		require.Equal(t, DedupModeSuppress, d.Mode)
+		require.Equal(t, 3, d.LineWindow)
+		require.Equal(t, 0.6, d.MinSimilarity)
+	})
+
+	t.Run("fails for unknown mode", func(t *testing.T) {
+		d := &Dedup{Enabled: true, Mode: "link"}
+		require.EqualError(t, d.Validate(), `review.dedup.mode must be "suppress" or "reply"`)
+	})
+
+	t.Run("fails for similarity out of range", func(t *testing.T) {
+		d := &Dedup{Enabled: true, MinSimilarity: 2}
+		require.EqualError(t, d.Validate(), "review.dedup.minSimilarity must be between 0 and 1")
+	})
+
+	t.Run("skips validation when disabled", func(t *testing.T) {
+		d := &Dedup{Mode: "link"}
+		require.NoError(t, d.Validate())
+	})
+}
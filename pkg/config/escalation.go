@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
+)
+
+const defaultEscalationLabel = "ai-escalated"
+
+// Escalation configures handing a discussion over to a human reviewer when the bot and the author
+// cannot agree: the thread reached replies.maxPerThread, or the reply model asked for escalation.
+type Escalation struct {
+	Enabled bool `yaml:"enabled"`
+	// Label is added to escalated discussions; the bot does not reply in them any more.
+	Label string `yaml:"label"`
+	// CodeOwners asks the Upsource owner of the discussed file first, when there is one.
+	CodeOwners bool `yaml:"codeOwners"`
+	// Reviewers are the Upsource user IDs asked in projects without reviewers of their own.
+	Reviewers []string `yaml:"reviewers"`
+	// Projects maps Upsource project IDs to the user IDs of their designated reviewers.
+	Projects map[string][]string `yaml:"projects"`
+	// SystemMessage overrides the built-in instructions for summarising both positions.
+	SystemMessage string `yaml:"systemMessage"`
+}
+
+func (e *Escalation) Validate() error {
+	if !e.Enabled {
+		return nil
+	}
+
+	if e.Label == "" {
+		e.Label = defaultEscalationLabel
+	}
+
+	if !e.CodeOwners && len(e.Reviewers) == 0 && len(e.Projects) == 0 {
+		return fmt.Errorf("replies.escalation needs reviewers, projects or codeOwners")
+	}
+	for projectID, reviewers := range e.Projects {
+		if len(reviewers) == 0 {
+			return fmt.Errorf("replies.escalation.projects.%s has no reviewers", projectID)
+		}
+	}
+
+	if err := prompt.Validate("replies.escalation.systemMessage", e.SystemMessage); err != nil {
+		return fmt.Errorf("replies.escalation.systemMessage is not a valid template: %w", err)
+	}
+
+	return nil
+}
+
+// ReviewersFor returns the designated reviewers of a project.
+func (e Escalation) ReviewersFor(projectID string) []string {
+	if reviewers, ok := e.Projects[projectID]; ok {
+		return reviewers
+	}
+
+	return e.Reviewers
+}
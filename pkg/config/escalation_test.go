@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscalationValidate(t *testing.T) {
+	t.Run("sets default label", func(t *testing.T) {
+		e := &Escalation{Enabled: true, CodeOwners: true}
+		require.NoError(t, e.Validate())
+		require.Equal(t, defaultEscalationLabel, e.Label)
+	})
+
+	t.Run("fails without anyone to escalate to", func(t *testing.T) {
+		e := &Escalation{Enabled: true}
+		require.EqualError(t, e.Validate(), "replies.escalation needs reviewers, projects or codeOwners")
+	})
+
+	t.Run("fails for project without reviewers", func(t *testing.T) {
+		e := &Escalation{Enabled: true, Projects: map[string][]string{"backend": nil}}
+		require.EqualError(t, e.Validate(), "replies.escalation.projects.backend has no reviewers")
+	})
+
+	t.Run("fails for unknown template variable", func(t *testing.T) {
+		e := &Escalation{Enabled: true, Reviewers: []string{"u1"}, SystemMessage: "{{.Ticket}}"}
+		require.EqualError(t, e.Validate(), "replies.escalation.systemMessage is not a valid template: unknown variable .Ticket")
+	})
+}
+
+func TestEscalationReviewersFor(t *testing.T) {
+	e := Escalation{Reviewers: []string{"lead"}, Projects: map[string][]string{"backend": {"alice", "bob"}}}
+	require.Equal(t, []string{"alice", "bob"}, e.ReviewersFor("backend"))
+	require.Equal(t, []string{"lead"}, e.ReviewersFor("frontend"))
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
+)
+
+const defaultSettleAfterDays = 7
+
+// Feedback configures tracking how developers respond to the comments of the bot.
+type Feedback struct {
+	Enabled bool `yaml:"enabled"`
+	// SettleAfterDays is how long an unresolved discussion may stay quiet before its outcome is classified.
+	SettleAfterDays int `yaml:"settleAfterDays"`
+	// SystemMessage overrides the built-in instructions for classifying the replies of a discussion.
+	SystemMessage string `yaml:"systemMessage"`
+}
+
+func (f *Feedback) Validate() error {
+	if !f.Enabled {
+		return nil
+	}
+
+	if f.SettleAfterDays < 0 {
+		return fmt.Errorf("feedback.settleAfterDays must be >= 0")
+	}
+	if f.SettleAfterDays == 0 {
+		f.SettleAfterDays = defaultSettleAfterDays
+	}
+
+	if err := prompt.Validate("feedback.systemMessage", f.SystemMessage); err != nil {
+		return fmt.Errorf("feedback.systemMessage is not a valid template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedbackValidate(t *testing.T) {
+	t.Run("sets default settle time", func(t *testing.T) {
+		f := &Feedback{Enabled: true}
+		require.NoError(t, f.Validate())
+		require.Equal(t, defaultSettleAfterDays, f.SettleAfterDays)
+	})
+
+	t.Run("fails for negative settle time", func(t *testing.T) {
+		f := &Feedback{Enabled: true, SettleAfterDays: -1}
+		require.EqualError(t, f.Validate(), "feedback.settleAfterDays must be >= 0")
+	})
+
+	t.Run("skips validation when disabled", func(t *testing.T) {
+		f := &Feedback{SettleAfterDays: -1}
+		require.NoError(t, f.Validate())
+	})
+}
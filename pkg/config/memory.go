@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
+)
+
+const (
+	defaultMemoryMaxThreadTokens = 2000
+	defaultMemoryKeepRecent      = 4
+)
+
+// Memory configures the summarisation of long reply threads.
+type Memory struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxThreadTokens is the estimated size above which older messages of a thread are summarised.
+	MaxThreadTokens int `yaml:"maxThreadTokens"`
+	// KeepRecent is the number of latest messages that are always sent verbatim.
+	KeepRecent int `yaml:"keepRecent"`
+	// SystemMessage overrides the built-in summarisation instructions.
+	SystemMessage string `yaml:"systemMessage"`
+}
+
+func (m *Memory) Validate() error {
+	if !m.Enabled {
+		return nil
+	}
+
+	if m.MaxThreadTokens < 0 {
+		return fmt.Errorf("replies.memory.maxThreadTokens must be >= 0")
+	}
+	if m.MaxThreadTokens == 0 {
+		m.MaxThreadTokens = defaultMemoryMaxThreadTokens
+	}
+
+	if m.KeepRecent < 0 {
+		return fmt.Errorf("replies.memory.keepRecent must be >= 0")
+	}
+	if m.KeepRecent == 0 {
+		m.KeepRecent = defaultMemoryKeepRecent
+	}
+
+	if err := prompt.Validate("replies.memory.systemMessage", m.SystemMessage); err != nil {
+		return fmt.Errorf("replies.memory.systemMessage is not a valid template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryValidate(t *testing.T) {
+	t.Run("sets defaults", func(t *testing.T) {
+		m := &Memory{Enabled: true}
+		require.NoError(t, m.Validate())
+		require.Equal(t, defaultMemoryMaxThreadTokens, m.MaxThreadTokens)
+		require.Equal(t, defaultMemoryKeepRecent, m.KeepRecent)
+	})
+
+	t.Run("fails for negative token limit", func(t *testing.T) {
+		m := &Memory{Enabled: true, MaxThreadTokens: -1}
+		require.EqualError(t, m.Validate(), "replies.memory.maxThreadTokens must be >= 0")
+	})
+
+	t.Run("fails for negative keep recent", func(t *testing.T) {
+		m := &Memory{Enabled: true, KeepRecent: -1}
+		require.EqualError(t, m.Validate(), "replies.memory.keepRecent must be >= 0")
+	})
+
+	t.Run("skips validation when disabled", func(t *testing.T) {
+		m := &Memory{MaxThreadTokens: -1}
+		require.NoError(t, m.Validate())
+	})
+}
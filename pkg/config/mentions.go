@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
+)
+
+// Mentions configures answering comments that mention the bot in discussions started by humans.
+type Mentions struct {
+	Enabled bool `yaml:"enabled"`
+	// TriggerWords wake the bot up in addition to a mention of its Upsource login, e.g. "@ai".
+	TriggerWords []string `yaml:"triggerWords"`
+	// SystemMessage overrides the built-in instructions for answering mentions.
+	SystemMessage string `yaml:"systemMessage"`
+}
+
+func (m *Mentions) Validate() error {
+	if !m.Enabled {
+		return nil
+	}
+
+	for i, word := range m.TriggerWords {
+		if strings.TrimSpace(word) == "" {
+			return fmt.Errorf("replies.mentions.triggerWords[%d] is empty", i)
+		}
+	}
+
+	if err := prompt.Validate("replies.mentions.systemMessage", m.SystemMessage); err != nil {
+		return fmt.Errorf("replies.mentions.systemMessage is not a valid template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionsValidate(t *testing.T) {
+	t.Run("accepts trigger words", func(t *testing.T) {
+		m := &Mentions{Enabled: true, TriggerWords: []string{"@ai"}}
+		require.NoError(t, m.Validate())
+	})
+
+	t.Run("fails for empty trigger word", func(t *testing.T) {
+		m := &Mentions{Enabled: true, TriggerWords: []string{"@ai", " "}}
+		require.EqualError(t, m.Validate(), "replies.mentions.triggerWords[1] is empty")
+	})
+
+	t.Run("fails for unknown template variable", func(t *testing.T) {
+		m := &Mentions{Enabled: true, SystemMessage: "{{.Ticket}}"}
+		require.EqualError(t, m.Validate(), "replies.mentions.systemMessage is not a valid template: unknown variable .Ticket")
+	})
+}
@@ -0,0 +1,27 @@
+package config
+
+import "fmt"
+
+// Participant configures the bot taking part in the review workflow as a reviewer.
+type Participant struct {
+	Enabled bool `yaml:"enabled"`
+	// RejectMinSeverity is the lowest severity of a posted comment that makes the bot reject the review.
+	// Reviews without such comments are accepted.
+	RejectMinSeverity string `yaml:"rejectMinSeverity"`
+}
+
+func (p *Participant) Validate() error {
+	if !p.Enabled {
+		return nil
+	}
+
+	switch p.RejectMinSeverity {
+	case "":
+		p.RejectMinSeverity = "high"
+	case "low", "medium", "high":
+	default:
+		return fmt.Errorf("review.participant.rejectMinSeverity must be one of low, medium or high")
+	}
+
+	return nil
+}
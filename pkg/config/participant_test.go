@@ -0,0 +1,20 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParticipantValidate(t *testing.T) {
+	t.Run("defaults to high", func(t *testing.T) {
+		p := &Participant{Enabled: true}
+		require.NoError(t, p.Validate())
+		require.Equal(t, "high", p.RejectMinSeverity)
+	})
+
+	t.Run("fails for unknown severity", func(t *testing.T) {
+		p := &Participant{Enabled: true, RejectMinSeverity: "blocker"}
+		require.EqualError(t, p.Validate(), "review.participant.rejectMinSeverity must be one of low, medium or high")
+	})
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// PromptFragment is an extra part of the review system prompt that is added only when
+// the diff touches files matching one of its globs, e.g. a checklist for SQL migrations.
+type PromptFragment struct {
+	Name string `yaml:"name"`
+	// Globs without a slash match the file name ("*.sql", "Dockerfile"), globs with a slash match
+	// the repository-relative path, where "**" matches any number of directories ("deploy/**/*.tf").
+	Globs []string `yaml:"globs"`
+	Text  string   `yaml:"text"`
+}
+
+func (f *PromptFragment) Validate() error {
+	if strings.TrimSpace(f.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(f.Text) == "" {
+		return fmt.Errorf("%s: text is required", f.Name)
+	}
+	if len(f.Globs) == 0 {
+		return fmt.Errorf("%s: globs must not be empty", f.Name)
+	}
+	for _, glob := range f.Globs {
+		if _, err := path.Match(strings.ReplaceAll(glob, "**", "*"), ""); err != nil {
+			return fmt.Errorf("%s: invalid glob %q: %w", f.Name, glob, err)
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether a repository-relative file path matches one of the fragment's globs.
+func (f *PromptFragment) Matches(filePath string) bool {
+	filePath = strings.TrimPrefix(filePath, "/")
+
+	for _, glob := range f.Globs {
+		if !strings.Contains(glob, "/") {
+			if ok, _ := path.Match(glob, path.Base(filePath)); ok {
+				return true
+			}
+			continue
+		}
+
+		if matchSegments(strings.Split(strings.TrimPrefix(glob, "/"), "/"), strings.Split(filePath, "/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchSegments matches path segments against glob segments, where a "**" segment matches zero or more segments.
+func matchSegments(glob, segments []string) bool {
+	if len(glob) == 0 {
+		return len(segments) == 0
+	}
+
+	if glob[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(glob[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(glob[0], segments[0]); !ok {
+		return false
+	}
+
+	return matchSegments(glob[1:], segments[1:])
+}
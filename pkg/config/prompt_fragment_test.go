@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptFragmentMatches(t *testing.T) {
+	tests := []struct {
+		globs []string
+		path  string
+		want  bool
+	}{
+		{globs: []string{"*.sql"}, path: "db/migrations/001_init.sql", want: true},
+		{globs: []string{"Dockerfile", "*.dockerfile"}, path: "build/Dockerfile", want: true},
+		{globs: []string{"*.go"}, path: "main.go.orig", want: false},
+		{globs: []string{"deploy/**/*.tf"}, path: "deploy/prod/eu/main.tf", want: true},
+		{globs: []string{"deploy/**/*.tf"}, path: "deploy/main.tf", want: true},
+		{globs: []string{"deploy/**/*.tf"}, path: "infra/deploy/main.tf", want: false},
+		{globs: []string{"migrations/*.sql"}, path: "/migrations/002.sql", want: true},
+	}
+
+	for _, tt := range tests {
+		f := &PromptFragment{Name: "f", Globs: tt.globs, Text: "t"}
+		require.Equal(t, tt.want, f.Matches(tt.path), "%v %s", tt.globs, tt.path)
+	}
+}
+
+func TestPromptFragmentValidate(t *testing.T) {
+	require.NoError(t, (&PromptFragment{Name: "sql", Globs: []string{"**/*.sql"}, Text: "Check locks."}).Validate())
+	require.EqualError(t, (&PromptFragment{Name: "sql", Text: "Check locks."}).Validate(), "sql: globs must not be empty")
+	require.EqualError(t, (&PromptFragment{Name: "sql", Globs: []string{"[*.sql"}, Text: "t"}).Validate(), `sql: invalid glob "[*.sql": syntax error in pattern`)
+}
@@ -87,6 +87,22 @@ func (p *Providers) AnthropicEnabled() bool {
 	return strings.TrimSpace(p.Anthropic.APIKey) != ""
 }
 
+// Enabled reports whether the named provider is configured.
+func (p *Providers) Enabled(name string) bool {
+	switch name {
+	case ProviderAgent:
+		return p.AgentEnabled()
+	case ProviderOpenAI:
+		return p.OpenAIEnabled()
+	case ProviderGemini:
+		return p.GeminiEnabled()
+	case ProviderAnthropic:
+		return p.AnthropicEnabled()
+	default:
+		return false
+	}
+}
+
 func (p *Providers) ActiveLLMProvider() string {
 	if p.AgentEnabled() {
 		return ProviderAgent
@@ -103,3 +119,21 @@ func (p *Providers) ActiveLLMProvider() string {
 
 	return unknownLLMProvider
 }
+
+// ActiveModel names the active provider and its model, e.g. "openai/gpt-5-mini".
+// The agent command has no configured model, so it is named "agent".
+func (p *Providers) ActiveModel() string {
+	var model string
+	switch provider := p.ActiveLLMProvider(); provider {
+	case ProviderOpenAI:
+		model = p.OpenAI.Model
+	case ProviderGemini:
+		model = p.Gemini.Model
+	case ProviderAnthropic:
+		model = p.Anthropic.Model
+	default:
+		return provider
+	}
+
+	return p.ActiveLLMProvider() + "/" + strings.TrimSpace(model)
+}
@@ -134,3 +134,9 @@ func TestProvidersActiveLLMProvider(t *testing.T) {
 		require.Equal(t, unknownLLMProvider, providers.ActiveLLMProvider())
 	})
 }
+
+func TestProvidersActiveModel(t *testing.T) {
+	require.Equal(t, "agent", (&Providers{Agent: Agent{Command: "codex"}}).ActiveModel())
+	require.Equal(t, "openai/gpt-5-mini", (&Providers{OpenAI: OpenAI{APIKey: "openai", Model: "gpt-5-mini"}}).ActiveModel())
+	require.Equal(t, "anthropic/claude-opus-4-1", (&Providers{Anthropic: Anthropic{APIKey: "anthropic", Model: "claude-opus-4-1"}}).ActiveModel())
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	RedactionDetectorAWS        = "aws"
+	RedactionDetectorPrivateKey = "private-key"
+	RedactionDetectorJWT        = "jwt"
+	RedactionDetectorEntropy    = "entropy"
+	RedactionDetectorEmail      = "email"
+)
+
+const defaultEntropyThreshold = 4.0
+
+// Redaction configures masking of secrets and personal data before any text is sent to an LLM provider.
+type Redaction struct {
+	Enabled bool `yaml:"enabled"`
+	// Detectors lists the built-in detectors to use. Empty means all of them.
+	Detectors      []string           `yaml:"detectors"`
+	CustomPatterns []RedactionPattern `yaml:"customPatterns"`
+	// EntropyThreshold is the minimal Shannon entropy (bits per char) of a token flagged by the entropy detector.
+	EntropyThreshold float64 `yaml:"entropyThreshold"`
+	// CommentOnSecrets posts a high severity "possible secret committed" comment at every added line
+	// where a secret was detected.
+	CommentOnSecrets bool `yaml:"commentOnSecrets"`
+}
+
+type RedactionPattern struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+	// Secret marks matches as credentials rather than personal data.
+	Secret bool `yaml:"secret"`
+}
+
+func (r *Redaction) Validate() error {
+	if !r.Enabled {
+		return nil
+	}
+
+	for _, detector := range r.Detectors {
+		switch detector {
+		case RedactionDetectorAWS, RedactionDetectorPrivateKey, RedactionDetectorJWT, RedactionDetectorEntropy, RedactionDetectorEmail:
+		default:
+			return fmt.Errorf("redaction.detectors contains unknown detector %q", detector)
+		}
+	}
+
+	for i, p := range r.CustomPatterns {
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("redaction.customPatterns[%d].name is required", i)
+		}
+		if _, err := regexp.Compile(p.Pattern); err != nil || p.Pattern == "" {
+			return fmt.Errorf("redaction.customPatterns[%d].pattern is not a valid regular expression", i)
+		}
+	}
+
+	if r.EntropyThreshold == 0 {
+		r.EntropyThreshold = defaultEntropyThreshold
+	}
+
+	return nil
+}
+
+// DetectorEnabled reports whether the given built-in detector is active.
+func (r *Redaction) DetectorEnabled(name string) bool {
+	if len(r.Detectors) == 0 {
+		return true
+	}
+
+	for _, detector := range r.Detectors {
+		if detector == name {
+			return true
+		}
+	}
+
+	return false
+}
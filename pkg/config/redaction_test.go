@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactionValidate(t *testing.T) {
+	t.Run("sets default entropy threshold", func(t *testing.T) {
+		r := &Redaction{Enabled: true}
+		require.NoError(t, r.Validate())
+		require.Equal(t, defaultEntropyThreshold, r.EntropyThreshold)
+	})
+
+	t.Run("fails for unknown detector", func(t *testing.T) {
+		r := &Redaction{Enabled: true, Detectors: []string{"aws", "ssn"}}
+		require.EqualError(t, r.Validate(), `redaction.detectors contains unknown detector "ssn"`)
+	})
+
+	t.Run("fails for invalid custom pattern", func(t *testing.T) {
+		r := &Redaction{Enabled: true, CustomPatterns: []RedactionPattern{{Name: "ticket", Pattern: "("}}}
+		require.EqualError(t, r.Validate(), "redaction.customPatterns[0].pattern is not a valid regular expression")
+	})
+
+	t.Run("enables all detectors by default", func(t *testing.T) {
+		r := &Redaction{Enabled: true}
+		require.True(t, r.DetectorEnabled(RedactionDetectorEmail))
+
+		r.Detectors = []string{RedactionDetectorAWS}
+		require.True(t, r.DetectorEnabled(RedactionDetectorAWS))
+		require.False(t, r.DetectorEnabled(RedactionDetectorEmail))
+	})
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"strings"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
 )
 
 type Review struct {
@@ -18,6 +20,62 @@ type Review struct {
 	SystemMessageOutputFormat string `yaml:"systemMessageOutputFormat"`
 
 	UserPromptTemplate string `yaml:"userPromptTemplate"`
+
+	// PromptFragments are added to the system message only when the diff touches matching files.
+	PromptFragments []PromptFragment `yaml:"promptFragments"`
+
+	Critique    Critique    `yaml:"critique"`
+	Posting     Posting     `yaml:"posting"`
+	Dedup       Dedup       `yaml:"dedup"`
+	Summary     Summary     `yaml:"summary"`
+	Participant Participant `yaml:"participant"`
+}
+
+// Posting decides where review comments end up based on their severity and confidence.
+// Empty severities and zero confidences disable the respective check.
+type Posting struct {
+	// InlineMinSeverity and InlineMinConfidence gate comments posted as separate discussions at a line.
+	InlineMinSeverity   string  `yaml:"inlineMinSeverity"`
+	InlineMinConfidence float64 `yaml:"inlineMinConfidence"`
+	// AggregateMinSeverity and AggregateMinConfidence gate comments collected in the low-priority discussion.
+	AggregateMinSeverity   string  `yaml:"aggregateMinSeverity"`
+	AggregateMinConfidence float64 `yaml:"aggregateMinConfidence"`
+	// LogOnlyBelowConfidence drops comments below this confidence before anything else; they are only logged.
+	LogOnlyBelowConfidence float64 `yaml:"logOnlyBelowConfidence"`
+}
+
+func (p *Posting) Validate() error {
+	// Checked in a fixed order, so the same error is reported when several fields are invalid.
+	severities := []struct {
+		name  string
+		value string
+	}{
+		{"inlineMinSeverity", p.InlineMinSeverity},
+		{"aggregateMinSeverity", p.AggregateMinSeverity},
+	}
+	for _, severity := range severities {
+		switch severity.value {
+		case "", "low", "medium", "high":
+		default:
+			return fmt.Errorf("review.posting.%s must be one of low, medium or high", severity.name)
+		}
+	}
+
+	confidences := []struct {
+		name  string
+		value float64
+	}{
+		{"inlineMinConfidence", p.InlineMinConfidence},
+		{"aggregateMinConfidence", p.AggregateMinConfidence},
+		{"logOnlyBelowConfidence", p.LogOnlyBelowConfidence},
+	}
+	for _, confidence := range confidences {
+		if confidence.value < 0 || confidence.value > 1 {
+			return fmt.Errorf("review.posting.%s must be between 0 and 1", confidence.name)
+		}
+	}
+
+	return nil
 }
 
 func (r *Review) Validate() error {
@@ -53,6 +111,31 @@ func (r *Review) Validate() error {
 		return fmt.Errorf("review.userPromptTemplate is not a valid template (expected placeholder for messages like {{messages}})")
 	}
 
+	if err := prompt.Validate("review.systemMessage", r.SystemMessageTemplate()); err != nil {
+		return fmt.Errorf("review.systemMessage is not a valid template: %w", err)
+	}
+	if err := prompt.Validate("review.userPromptTemplate", r.UserPromptTemplate); err != nil {
+		return fmt.Errorf("review.userPromptTemplate is not a valid template: %w", err)
+	}
+
+	for i := range r.PromptFragments {
+		if err := r.PromptFragments[i].Validate(); err != nil {
+			return fmt.Errorf("review.promptFragments[%d]: %w", i, err)
+		}
+	}
+
+	if err := r.Posting.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.Dedup.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.Participant.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -74,13 +157,13 @@ func (r *Review) usesSplitSystemMessage() bool {
 }
 
 func containsMaxPerReviewPlaceholder(s string) bool {
-	return strings.Contains(s, "{{max_per_review}}")
+	return prompt.Uses(s, "MaxPerReview")
 }
 
 func containsDiffsPlaceholder(s string) bool {
-	return strings.Contains(s, "{{diffs}}")
+	return prompt.Uses(s, "Diff")
 }
 
 func containsMessagesPlaceholder(s string) bool {
-	return strings.Contains(s, "{{messages}}")
+	return prompt.Uses(s, "Messages")
 }
@@ -52,3 +52,45 @@ func validReview() *Review {
 		UserPromptTemplate:        "diffs: {{diffs}}\nmessages: {{messages}}",
 	}
 }
+
+func TestPostingValidate(t *testing.T) {
+	t.Run("allows empty thresholds", func(t *testing.T) {
+		p := &Posting{}
+		require.NoError(t, p.Validate())
+	})
+
+	t.Run("fails for unknown severity", func(t *testing.T) {
+		p := &Posting{InlineMinSeverity: "critical"}
+		require.EqualError(t, p.Validate(), "review.posting.inlineMinSeverity must be one of low, medium or high")
+	})
+
+	t.Run("fails for confidence out of range", func(t *testing.T) {
+		p := &Posting{LogOnlyBelowConfidence: 1.5}
+		require.EqualError(t, p.Validate(), "review.posting.logOnlyBelowConfidence must be between 0 and 1")
+	})
+
+	t.Run("reports the same error for several invalid fields", func(t *testing.T) {
+		for range 20 {
+			p := &Posting{AggregateMinSeverity: "critical", InlineMinSeverity: "urgent", InlineMinConfidence: 2, LogOnlyBelowConfidence: -1}
+			require.EqualError(t, p.Validate(), "review.posting.inlineMinSeverity must be one of low, medium or high")
+
+			p = &Posting{LogOnlyBelowConfidence: -1, AggregateMinConfidence: 2, InlineMinConfidence: 3}
+			require.EqualError(t, p.Validate(), "review.posting.inlineMinConfidence must be between 0 and 1")
+		}
+	})
+}
+
+func TestReviewValidateTemplates(t *testing.T) {
+	t.Run("accepts template fields", func(t *testing.T) {
+		r := validReview()
+		r.SystemMessageGuidelines = "max {{.MaxPerReview}} comments for {{.Language}}"
+		r.UserPromptTemplate = "{{.Title}}\n{{.Diff}}\n{{.Messages}}"
+		require.NoError(t, r.Validate())
+	})
+
+	t.Run("fails for unknown variable", func(t *testing.T) {
+		r := validReview()
+		r.UserPromptTemplate = "{{diffs}} {{messages}} {{.Ticket}}"
+		require.EqualError(t, r.Validate(), "review.userPromptTemplate is not a valid template: unknown variable .Ticket")
+	})
+}
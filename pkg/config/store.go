@@ -0,0 +1,17 @@
+package config
+
+// Store configures the file the bot keeps its state in, such as muted reviews, suppressed findings,
+// comment feedback and thread summaries.
+type Store struct {
+	Path string `yaml:"path"`
+}
+
+const defaultStorePath = "reviewer-state.json"
+
+func (s *Store) Validate() error {
+	if s.Path == "" {
+		s.Path = defaultStorePath
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreValidate(t *testing.T) {
+	s := &Store{}
+	require.NoError(t, s.Validate())
+	require.Equal(t, "reviewer-state.json", s.Path)
+}
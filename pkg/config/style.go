@@ -0,0 +1,65 @@
+package config
+
+import "fmt"
+
+const (
+	ToneConcise   = "concise"
+	ToneMentoring = "mentoring"
+	ToneStrict    = "strict"
+)
+
+// LanguageAuto detects the output language from the review title, description and commit messages.
+const LanguageAuto = "auto"
+
+// Style configures the language and tone of review comments, summaries and replies.
+// Empty values leave the choice to the model.
+type Style struct {
+	// Language is the name of the output language, e.g. "English", or LanguageAuto.
+	Language string `yaml:"language"`
+	Tone     string `yaml:"tone"`
+	// Projects overrides the language and tone per Upsource project ID.
+	Projects map[string]ProjectStyle `yaml:"projects"`
+}
+
+type ProjectStyle struct {
+	Language string `yaml:"language"`
+	Tone     string `yaml:"tone"`
+}
+
+func (s *Style) Validate() error {
+	if err := validateTone("style.tone", s.Tone); err != nil {
+		return err
+	}
+
+	for projectID, p := range s.Projects {
+		if err := validateTone(fmt.Sprintf("style.projects.%s.tone", projectID), p.Tone); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateTone(name, tone string) error {
+	switch tone {
+	case "", ToneConcise, ToneMentoring, ToneStrict:
+		return nil
+	default:
+		return fmt.Errorf("%s must be one of %s, %s or %s", name, ToneConcise, ToneMentoring, ToneStrict)
+	}
+}
+
+// For returns the language and tone for a project, falling back to the defaults for values the project does not set.
+func (s Style) For(projectID string) (language, tone string) {
+	language, tone = s.Language, s.Tone
+	if p, ok := s.Projects[projectID]; ok {
+		if p.Language != "" {
+			language = p.Language
+		}
+		if p.Tone != "" {
+			tone = p.Tone
+		}
+	}
+
+	return language, tone
+}
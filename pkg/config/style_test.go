@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStyleValidate(t *testing.T) {
+	t.Run("accepts presets", func(t *testing.T) {
+		s := &Style{Tone: ToneMentoring, Projects: map[string]ProjectStyle{"backend": {Tone: ToneStrict}}}
+		require.NoError(t, s.Validate())
+	})
+
+	t.Run("fails for unknown tone", func(t *testing.T) {
+		s := &Style{Tone: "friendly"}
+		require.EqualError(t, s.Validate(), "style.tone must be one of concise, mentoring or strict")
+	})
+
+	t.Run("fails for unknown project tone", func(t *testing.T) {
+		s := &Style{Projects: map[string]ProjectStyle{"backend": {Tone: "rude"}}}
+		require.EqualError(t, s.Validate(), "style.projects.backend.tone must be one of concise, mentoring or strict")
+	})
+}
+
+func TestStyleFor(t *testing.T) {
+	s := Style{
+		Language: LanguageAuto,
+		Tone:     ToneConcise,
+		Projects: map[string]ProjectStyle{"backend": {Language: "German"}},
+	}
+
+	language, tone := s.For("backend")
+	require.Equal(t, "German", language)
+	require.Equal(t, ToneConcise, tone)
+
+	language, tone = s.For("frontend")
+	require.Equal(t, LanguageAuto, language)
+	require.Equal(t, ToneConcise, tone)
+}
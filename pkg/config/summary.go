@@ -0,0 +1,9 @@
+package config
+
+// Summary configures the optional pass that posts an overall summary and verdict for each review.
+// When enabled, the summary discussion also lists the comments that are not posted inline.
+type Summary struct {
+	Enabled bool `yaml:"enabled"`
+	// SystemMessage overrides the built-in summary instructions.
+	SystemMessage string `yaml:"systemMessage"`
+}
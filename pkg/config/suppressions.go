@@ -0,0 +1,36 @@
+package config
+
+import "fmt"
+
+const (
+	defaultMinRejections          = 2
+	defaultMaxSuppressionExamples = 10
+)
+
+// Suppressions configures the kinds of findings the bot stops reporting, see the ignore command.
+type Suppressions struct {
+	// Learn stores findings developers rejected in replies, and stops reporting them once
+	// they were rejected MinRejections times in a project.
+	Learn         bool `yaml:"learn"`
+	MinRejections int  `yaml:"minRejections"`
+	// MaxExamples limits the suppressions listed as "do not report" examples in a review prompt.
+	MaxExamples int `yaml:"maxExamples"`
+}
+
+func (s *Suppressions) Validate() error {
+	if s.MinRejections < 0 {
+		return fmt.Errorf("suppressions.minRejections must be >= 0")
+	}
+	if s.MinRejections == 0 {
+		s.MinRejections = defaultMinRejections
+	}
+
+	if s.MaxExamples < 0 {
+		return fmt.Errorf("suppressions.maxExamples must be >= 0")
+	}
+	if s.MaxExamples == 0 {
+		s.MaxExamples = defaultMaxSuppressionExamples
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressionsValidate(t *testing.T) {
+	t.Run("sets defaults", func(t *testing.T) {
+		s := &Suppressions{}
+		require.NoError(t, s.Validate())
+		require.Equal(t, defaultMinRejections, s.MinRejections)
+		require.Equal(t, defaultMaxSuppressionExamples, s.MaxExamples)
+	})
+
+	t.Run("fails for negative min rejections", func(t *testing.T) {
+		s := &Suppressions{MinRejections: -1}
+		require.EqualError(t, s.Validate(), "suppressions.minRejections must be >= 0")
+	})
+
+	t.Run("fails for negative max examples", func(t *testing.T) {
+		s := &Suppressions{MaxExamples: -1}
+		require.EqualError(t, s.Validate(), "suppressions.maxExamples must be >= 0")
+	})
+}
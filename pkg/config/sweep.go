@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/groall/upsource-ai-reviewer/pkg/prompt"
+)
+
+const defaultSweepIntervalMinutes = 60
+
+// Sweep configures the periodic check of unanswered bot discussions whose code was changed since.
+type Sweep struct {
+	Enabled bool `yaml:"enabled"`
+	// IntervalMinutes is the minimum time between two sweeps; the sweep runs with the reply pass.
+	IntervalMinutes int `yaml:"intervalMinutes"`
+	// SystemMessage overrides the built-in instructions for deciding whether an issue still applies.
+	SystemMessage string `yaml:"systemMessage"`
+}
+
+func (s *Sweep) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+
+	if s.IntervalMinutes < 0 {
+		return fmt.Errorf("replies.sweep.intervalMinutes must be >= 0")
+	}
+	if s.IntervalMinutes == 0 {
+		s.IntervalMinutes = defaultSweepIntervalMinutes
+	}
+
+	if err := prompt.Validate("replies.sweep.systemMessage", s.SystemMessage); err != nil {
+		return fmt.Errorf("replies.sweep.systemMessage is not a valid template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepValidate(t *testing.T) {
+	t.Run("sets default interval", func(t *testing.T) {
+		s := &Sweep{Enabled: true}
+		require.NoError(t, s.Validate())
+		require.Equal(t, defaultSweepIntervalMinutes, s.IntervalMinutes)
+	})
+
+	t.Run("fails for negative interval", func(t *testing.T) {
+		s := &Sweep{Enabled: true, IntervalMinutes: -1}
+		require.EqualError(t, s.Validate(), "replies.sweep.intervalMinutes must be >= 0")
+	})
+
+	t.Run("fails for unknown template variable", func(t *testing.T) {
+		s := &Sweep{Enabled: true, SystemMessage: "{{.Ticket}}"}
+		require.EqualError(t, s.Validate(), "replies.sweep.systemMessage is not a valid template: unknown variable .Ticket")
+	})
+}
@@ -0,0 +1,64 @@
+package config
+
+import "fmt"
+
+const (
+	TrackerTypeYouTrack = "youtrack"
+	TrackerTypeJira     = "jira"
+)
+
+const (
+	defaultTrackerMaxIssues      = 3
+	defaultTrackerMaxIssueLength = 2000
+)
+
+// Tracker configures fetching the issues mentioned in a review from YouTrack or Jira
+// to include their summary, description and acceptance criteria in the review prompt.
+type Tracker struct {
+	Enabled bool   `yaml:"enabled"`
+	Type    string `yaml:"type"`
+	BaseURL string `yaml:"baseUrl"`
+	// Token is a YouTrack permanent token, or a Jira API token (with Email) or personal access token.
+	Token string `yaml:"token"`
+	// Email enables Jira Cloud basic authentication with Token as the API token.
+	Email string `yaml:"email"`
+	// AcceptanceCriteriaField is the name (YouTrack) or ID (Jira, e.g. "customfield_10042") of the
+	// custom field holding the acceptance criteria.
+	AcceptanceCriteriaField string `yaml:"acceptanceCriteriaField"`
+	// MaxIssues limits the number of issues fetched per review.
+	MaxIssues int `yaml:"maxIssues"`
+	// MaxIssueLength limits the characters of each issue included in the prompt.
+	MaxIssueLength int `yaml:"maxIssueLength"`
+}
+
+func (t *Tracker) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+
+	switch t.Type {
+	case TrackerTypeYouTrack, TrackerTypeJira:
+	default:
+		return fmt.Errorf("tracker.type must be %s or %s", TrackerTypeYouTrack, TrackerTypeJira)
+	}
+
+	if t.BaseURL == "" {
+		return fmt.Errorf("tracker.baseUrl is required")
+	}
+
+	if t.Token == "" {
+		return fmt.Errorf("tracker.token is required")
+	}
+
+	if t.MaxIssues < 0 || t.MaxIssueLength < 0 {
+		return fmt.Errorf("tracker.maxIssues and tracker.maxIssueLength must be >= 0")
+	}
+	if t.MaxIssues == 0 {
+		t.MaxIssues = defaultTrackerMaxIssues
+	}
+	if t.MaxIssueLength == 0 {
+		t.MaxIssueLength = defaultTrackerMaxIssueLength
+	}
+
+	return nil
+}
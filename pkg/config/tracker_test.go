@@ -0,0 +1,31 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerValidate(t *testing.T) {
+	t.Run("disabled tracker is not validated", func(t *testing.T) {
+		tr := &Tracker{}
+		require.NoError(t, tr.Validate())
+	})
+
+	t.Run("sets defaults", func(t *testing.T) {
+		tr := &Tracker{Enabled: true, Type: TrackerTypeJira, BaseURL: "https://jira.example", Token: "token"}
+		require.NoError(t, tr.Validate())
+		require.Equal(t, 3, tr.MaxIssues)
+		require.Equal(t, 2000, tr.MaxIssueLength)
+	})
+
+	t.Run("fails for unknown type", func(t *testing.T) {
+		tr := &Tracker{Enabled: true, Type: "redmine", BaseURL: "https://tracker.example", Token: "token"}
+		require.EqualError(t, tr.Validate(), "tracker.type must be youtrack or jira")
+	})
+
+	t.Run("requires token", func(t *testing.T) {
+		tr := &Tracker{Enabled: true, Type: TrackerTypeYouTrack, BaseURL: "https://youtrack.example"}
+		require.EqualError(t, tr.Validate(), "tracker.token is required")
+	})
+}
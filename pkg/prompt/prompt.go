@@ -0,0 +1,227 @@
+// Package prompt renders the configurable LLM prompts with text/template.
+//
+// Templates are executed against Data, e.g.:
+//
+//	Review "{{.Title}}" ({{join .Issues ", "}}) on {{.Branch}} by {{.Author}} changes {{.Stats.Files}} files
+//	(+{{.Stats.Added}}/-{{.Stats.Removed}}), mostly {{.Language}}.
+//	{{range .Files}}- {{.Path}}
+//	{{end}}
+//	{{.Diff}}
+//
+// The legacy placeholders {{diffs}}, {{messages}} and {{max_per_review}} are still accepted
+// and mean {{.Diff}}, {{.Messages}} and {{.MaxPerReview}}.
+package prompt
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Data is the data model available to prompt templates.
+type Data struct {
+	Title        string   // Review title.
+	Description  string   // Review description.
+	Branch       string   // Review branch.
+	Author       string   // Review author, by display name when it is known.
+	Reviewers    []string // Review participants with the reviewer role, by display name when it is known.
+	Issues       []string // Issue keys linked to the review or found in its title and branch, e.g. "PROJ-123".
+	Project      string   // Upsource project ID.
+	Labels       []string // Review labels.
+	Files        []File   // Files changed in the diff.
+	Stats        Stats    // Totals over Files.
+	Diff         string   // The unified diff of the review.
+	Messages     string   // Commit messages.
+	MaxPerReview int      // Maximum number of comments per review.
+	Language     string   // Main programming language of the change, guessed from file extensions.
+}
+
+// File is a file changed in the diff.
+type File struct {
+	Path    string
+	Added   int
+	Removed int
+}
+
+// Stats summarises the changed files.
+type Stats struct {
+	Files   int
+	Added   int
+	Removed int
+}
+
+// Template is a parsed prompt template.
+type Template struct {
+	tmpl *template.Template
+}
+
+var legacyPlaceholders = strings.NewReplacer(
+	"{{diffs}}", "{{.Diff}}",
+	"{{messages}}", "{{.Messages}}",
+	"{{max_per_review}}", "{{.MaxPerReview}}",
+)
+
+// ConvertLegacy rewrites the legacy placeholders to template fields.
+func ConvertLegacy(text string) string {
+	return legacyPlaceholders.Replace(text)
+}
+
+var funcs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	// truncate shortens s to at most n characters.
+	"truncate": func(n int, s string) string {
+		if r := []rune(s); len(r) > n {
+			return string(r[:n]) + "…"
+		}
+		return s
+	},
+	// hasFile reports whether one of the files matches a file name glob such as "*.sql".
+	"hasFile": func(glob string, files []File) bool {
+		for _, f := range files {
+			if ok, _ := path.Match(glob, path.Base(f.Path)); ok {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// Parse parses a prompt template, converting legacy placeholders first.
+func Parse(name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(ConvertLegacy(text))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Execute renders the template with data.
+func (t *Template) Execute(data Data) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", describeError(err)
+	}
+
+	return b.String(), nil
+}
+
+var sampleData = Data{
+	Title:        "title",
+	Description:  "description",
+	Branch:       "branch",
+	Author:       "author",
+	Reviewers:    []string{"reviewer"},
+	Issues:       []string{"PROJ-1"},
+	Project:      "project",
+	Labels:       []string{"label"},
+	Files:        []File{{Path: "main.go", Added: 1, Removed: 1}},
+	Stats:        Stats{Files: 1, Added: 1, Removed: 1},
+	Diff:         "diff",
+	Messages:     "messages",
+	MaxPerReview: 1,
+	Language:     "Go",
+}
+
+// Validate parses the template and renders it with sample data, reporting syntax errors and unknown variables.
+func Validate(name, text string) error {
+	t, err := Parse(name, text)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.Execute(sampleData)
+	return err
+}
+
+// Uses reports whether the template references the field of Data, e.g. Uses(text, "Diff").
+func Uses(text, field string) bool {
+	return regexp.MustCompile(`\{\{[^}]*\.` + regexp.QuoteMeta(field) + `\b[^}]*\}\}`).MatchString(ConvertLegacy(text))
+}
+
+var unknownField = regexp.MustCompile(`at <(.+?)>: can't evaluate field (\w+)`)
+
+// describeError turns text/template's "can't evaluate field" errors into "unknown variable" errors.
+func describeError(err error) error {
+	if m := unknownField.FindStringSubmatch(err.Error()); m != nil {
+		return fmt.Errorf("unknown variable %s", m[1])
+	}
+
+	return err
+}
+
+var issueKey = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`)
+
+// IssueKeys returns the distinct issue keys such as "PROJ-123" found in the texts, in order of appearance.
+func IssueKeys(texts ...string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, key := range issueKey.FindAllString(text, -1) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
+
+var languages = map[string]string{
+	".go":    "Go",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".py":    "Python",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".php":   "PHP",
+	".rb":    "Ruby",
+	".rs":    "Rust",
+	".cs":    "C#",
+	".c":     "C",
+	".h":     "C",
+	".cpp":   "C++",
+	".swift": "Swift",
+	".scala": "Scala",
+	".sql":   "SQL",
+	".tf":    "Terraform",
+	".sh":    "Shell",
+}
+
+// GuessLanguage returns the programming language with the most changed lines, or "" when none is known.
+func GuessLanguage(files []File) string {
+	lines := make(map[string]int)
+	for _, f := range files {
+		if language, ok := languages[strings.ToLower(path.Ext(f.Path))]; ok {
+			lines[language] += f.Added + f.Removed
+		}
+	}
+
+	var best string
+	for language, n := range lines {
+		if best == "" || n > lines[best] || n == lines[best] && language < best {
+			best = language
+		}
+	}
+
+	return best
+}
+
+// StatsOf totals the changed files.
+func StatsOf(files []File) Stats {
+	stats := Stats{Files: len(files)}
+	for _, f := range files {
+		stats.Added += f.Added
+		stats.Removed += f.Removed
+	}
+
+	return stats
+}
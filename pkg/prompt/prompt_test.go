@@ -0,0 +1,60 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute(t *testing.T) {
+	tmpl, err := Parse("test", `{{.Title}} ({{.Branch}}, {{.Language}}) +{{.Stats.Added}}/-{{.Stats.Removed}}
+{{range .Files}}- {{.Path}}
+{{end}}{{if hasFile "*.sql" .Files}}SQL{{end}} {{join .Labels ", "}} {{truncate 3 .Messages}}
+max {{max_per_review}}
+{{diffs}}`)
+	require.NoError(t, err)
+
+	files := []File{{Path: "db/1.sql", Added: 2}, {Path: "main.go", Added: 5, Removed: 1}}
+	got, err := tmpl.Execute(Data{
+		Title:        "Add users",
+		Branch:       "feature/users",
+		Labels:       []string{"backend", "db"},
+		Files:        files,
+		Stats:        StatsOf(files),
+		Diff:         "+x {{not a template}}",
+		Messages:     "Add users table",
+		MaxPerReview: 5,
+		Language:     GuessLanguage(files),
+	})
+	require.NoError(t, err)
+	require.Equal(t, `Add users (feature/users, Go) +7/-1
+- db/1.sql
+- main.go
+SQL backend, db Add…
+max 5
++x {{not a template}}`, got)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate("ok", "{{diffs}} {{messages}} {{range .Files}}{{.Path}}{{end}}"))
+	require.EqualError(t, Validate("unknown", "{{.Diff}} {{.Ticket}}"), "unknown variable .Ticket")
+	require.EqualError(t, Validate("nested", "{{range .Files}}{{.Name}}{{end}}"), "unknown variable .Name")
+	require.Error(t, Validate("syntax", "{{.Diff"))
+}
+
+func TestUses(t *testing.T) {
+	require.True(t, Uses("{{diffs}}", "Diff"))
+	require.True(t, Uses("{{ .Diff | trim }}", "Diff"))
+	require.False(t, Uses("{{.DiffStats}}", "Diff"))
+	require.False(t, Uses(".Diff", "Diff"))
+}
+
+func TestGuessLanguage(t *testing.T) {
+	require.Equal(t, "Python", GuessLanguage([]File{{Path: "a.py", Added: 10}, {Path: "b.go", Added: 3}}))
+	require.Empty(t, GuessLanguage([]File{{Path: "README.md", Added: 10}}))
+}
+
+func TestIssueKeys(t *testing.T) {
+	require.Equal(t, []string{"PROJ-12", "OPS-7"}, IssueKeys("PROJ-12: fix login", "feature/OPS-7-PROJ-12-retry"))
+	require.Empty(t, IssueKeys("fix login", "master"))
+}
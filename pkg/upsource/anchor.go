@@ -0,0 +1,77 @@
+package upsource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/groall/upsource-go-client/client"
+)
+
+// FileAnchor is the code a discussion is anchored to, at the anchored revision and at the review head.
+type FileAnchor struct {
+	File       string // Repository-relative path.
+	RevisionID string // Revision the discussion is anchored to.
+	StartLine  int    // First anchored line; 0 when the anchor has no range.
+	EndLine    int    // Last anchored line.
+	Content    string // File content at RevisionID.
+	// HeadRevisionID is the latest revision of the file in the review, empty when the file
+	// is not among the review changes or its content could not be loaded.
+	HeadRevisionID string
+	HeadContent    string
+}
+
+// ResolveFileAnchor loads the file a discussion is anchored to at the anchored revision and at the review head
+// and converts the anchored range to line numbers.
+func ResolveFileAnchor(ctx context.Context, upsourceClient *client.Client, review *Review, anchor client.AnchorDTO) (*FileAnchor, error) {
+	if anchor.FileID == "" {
+		return nil, fmt.Errorf("discussion is not anchored to a file")
+	}
+
+	content, err := upsourceClient.GetFileContent(ctx, client.FileInRevisionDTO{
+		ProjectID:  review.GetProjectID(),
+		RevisionID: anchor.RevisionID,
+		FileName:   anchor.FileID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting file content for %s: %w", anchor.FileID, err)
+	}
+
+	fa := &FileAnchor{
+		File:       strings.TrimPrefix(anchor.FileID, "/"),
+		RevisionID: anchor.RevisionID,
+		Content:    content.FileContent.Text,
+	}
+	if anchor.Range != nil {
+		fa.StartLine = lineForOffset(fa.Content, anchor.Range.StartOffset)
+		fa.EndLine = lineForOffset(fa.Content, anchor.Range.EndOffset)
+		if fa.EndLine < fa.StartLine {
+			fa.EndLine = fa.StartLine
+		}
+	}
+
+	head, ok := review.headFile(anchor.FileID)
+	switch {
+	case !ok:
+	case head.RevisionID == anchor.RevisionID:
+		fa.HeadRevisionID, fa.HeadContent = fa.RevisionID, fa.Content
+	default:
+		// A file deleted at the head has no content there; the anchored revision is still useful.
+		if headContent, err := upsourceClient.GetFileContent(ctx, head); err == nil {
+			fa.HeadRevisionID, fa.HeadContent = head.RevisionID, headContent.FileContent.Text
+		}
+	}
+
+	return fa, nil
+}
+
+// headFile returns the latest revision of a file among the review changes.
+func (r *Review) headFile(fileName string) (client.FileInRevisionDTO, bool) {
+	for _, f := range r.filesDiffSummary {
+		if f.File.FileName == fileName || f.File.FileName == "/"+strings.TrimPrefix(fileName, "/") {
+			return f.File, true
+		}
+	}
+
+	return client.FileInRevisionDTO{}, false
+}
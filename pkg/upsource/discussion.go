@@ -33,6 +33,73 @@ func ListReviewDiscussions(ctx context.Context, upsourceClient *client.Client, r
 	return out, nil
 }
 
+// DiscussionLocation is the file and line a discussion is anchored to. File is empty for
+// discussions without a file anchor and Line is 0 when the line could not be determined.
+type DiscussionLocation struct {
+	File string
+	Line int
+}
+
+// ResolveDiscussionLocations converts the anchors of review discussions to repository-relative
+// file paths and 1-based line numbers, keyed by discussion ID. File contents are fetched once
+// per file revision; when fetching fails the line is left unknown.
+func ResolveDiscussionLocations(ctx context.Context, upsourceClient *client.Client, review *Review, discussions []client.DiscussionInFileDTO) map[string]DiscussionLocation {
+	contents := make(map[client.FileInRevisionDTO]string)
+	locations := make(map[string]DiscussionLocation, len(discussions))
+
+	for _, d := range discussions {
+		if d.Anchor.FileID == "" {
+			locations[d.DiscussionID] = DiscussionLocation{}
+			continue
+		}
+
+		location := DiscussionLocation{File: strings.TrimPrefix(d.Anchor.FileID, "/")}
+		if d.Anchor.Range != nil {
+			file := client.FileInRevisionDTO{
+				ProjectID:  review.GetProjectID(),
+				RevisionID: d.Anchor.RevisionID,
+				FileName:   d.Anchor.FileID,
+			}
+			text, ok := contents[file]
+			if !ok {
+				if fileContent, err := upsourceClient.GetFileContent(ctx, file); err == nil {
+					text = fileContent.FileContent.Text
+				}
+				contents[file] = text
+			}
+			location.Line = lineForOffset(text, d.Anchor.Range.StartOffset)
+		}
+
+		locations[d.DiscussionID] = location
+	}
+
+	return locations
+}
+
+// lineForOffset returns the 1-based line containing the rune offset, or 0 when it is outside the text.
+func lineForOffset(text string, offset int32) int {
+	if text == "" || offset < 0 {
+		return 0
+	}
+
+	line := 1
+	var pos int32
+	for _, r := range text {
+		if pos == offset {
+			return line
+		}
+		if r == '\n' {
+			line++
+		}
+		pos++
+	}
+	if pos == offset {
+		return line
+	}
+
+	return 0
+}
+
 // AddDiscussionComment posts a reply comment to an existing discussion.
 func AddDiscussionComment(ctx context.Context, upsourceClient *client.Client, projectID, discussionID, parentCommentID, text string) error {
 	_, err := upsourceClient.AddComment(ctx, client.AddCommentRequestDTO{
@@ -45,6 +112,18 @@ func AddDiscussionComment(ctx context.Context, upsourceClient *client.Client, pr
 	return err
 }
 
+// UpdateDiscussionComment replaces the text of a comment in a discussion.
+func UpdateDiscussionComment(ctx context.Context, upsourceClient *client.Client, projectID, discussionID, commentID, text string) error {
+	_, err := upsourceClient.UpdateComment(ctx, client.UpdateCommentRequestDTO{
+		ProjectID:    projectID,
+		DiscussionID: discussionID,
+		CommentID:    commentID,
+		Text:         text,
+		MarkupType:   markdownMarkupType,
+	})
+	return err
+}
+
 // ResolveDiscussion marks the given discussion as resolved.
 func ResolveDiscussion(ctx context.Context, upsourceClient *client.Client, projectID, discussionID string) error {
 	_, err := upsourceClient.ResolveDiscussion(ctx, client.ResolveDiscussionRequestDTO{
@@ -55,6 +134,25 @@ func ResolveDiscussion(ctx context.Context, upsourceClient *client.Client, proje
 	return err
 }
 
+// AddDiscussionLabel adds a label to the given discussion.
+func AddDiscussionLabel(ctx context.Context, upsourceClient *client.Client, projectID, discussionID, label string) error {
+	_, err := upsourceClient.AddDiscussionLabel(ctx, client.UpdateDiscussionLabelRequestDTO{
+		ProjectID:    projectID,
+		DiscussionID: discussionID,
+		Label:        client.LabelDTO{Name: label},
+	})
+	return err
+}
+
+// StarDiscussion stars the given discussion so it stays pinned at the top of the review.
+func StarDiscussion(ctx context.Context, upsourceClient *client.Client, projectID, discussionID string) error {
+	return upsourceClient.StarDiscussion(ctx, client.UpdateDiscussionFlagRequestDTO{
+		ProjectID:    projectID,
+		DiscussionID: discussionID,
+		IsFlagged:    true,
+	})
+}
+
 // ShouldReplyToDiscussion is the "should the bot reply now?" predicate.
 // Returns the last comment (parent target for the reply) and true when:
 //   - discussion carries reviewedLabel
@@ -102,19 +200,169 @@ func ShouldReplyToDiscussion(d client.DiscussionInFileDTO, reviewedLabel, botUse
 	return last, true
 }
 
+// EscalationNotePrefix starts the bot's note that hands a discussion over to human reviewers.
+const EscalationNotePrefix = "We could not agree here, so I asked "
+
+// ShouldEscalateDiscussion is the "should a human decide?" predicate for threads that reached the reply cap.
+// Returns the last comment (parent target for the escalation note) and true when the discussion:
+//   - carries reviewedLabel but not escalationLabel
+//   - has no escalation note of the bot yet (the label is added after the note and may be missing)
+//   - is not resolved
+//   - has a human comment after the bot's last word
+//   - already has maxPerThread comments of the bot (maxPerThread > 0)
+func ShouldEscalateDiscussion(d client.DiscussionInFileDTO, reviewedLabel, escalationLabel, botUserID string, maxPerThread int) (client.CommentDTO, bool) {
+	var zero client.CommentDTO
+
+	if maxPerThread <= 0 || !HasDiscussionLabel(d, reviewedLabel) || HasDiscussionLabel(d, escalationLabel) {
+		return zero, false
+	}
+	if HasEscalationNote(d, botUserID) {
+		return zero, false
+	}
+	if d.IsResolved != nil && *d.IsResolved || len(d.Comments) == 0 {
+		return zero, false
+	}
+
+	last := d.Comments[len(d.Comments)-1]
+	if last.AuthorID == botUserID {
+		return zero, false
+	}
+
+	var botCount int
+	for _, c := range d.Comments {
+		if c.AuthorID == botUserID {
+			botCount++
+		}
+	}
+	if botCount < maxPerThread {
+		return zero, false
+	}
+
+	return last, true
+}
+
+// HasDiscussionLabel reports whether a discussion carries the label.
+func HasDiscussionLabel(d client.DiscussionInFileDTO, label string) bool {
+	for _, l := range d.Labels {
+		if l.Name == label {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasEscalationNote reports whether the bot has already handed the discussion over to human reviewers.
+func HasEscalationNote(d client.DiscussionInFileDTO, botUserID string) bool {
+	for _, c := range d.Comments {
+		if c.AuthorID == botUserID && strings.HasPrefix(c.Text, EscalationNotePrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShouldRecheckDiscussion is the "may the bot's issue be stale?" predicate.
+// Returns the last comment (parent target for the closing note) and true when the discussion:
+//   - is anchored to a file and carries reviewedLabel
+//   - is not resolved
+//   - has only comments by the bot; once a human has replied, the thread is a conversation
+//     handled by ShouldReplyToDiscussion, and closing it would cut that conversation short
+func ShouldRecheckDiscussion(d client.DiscussionInFileDTO, reviewedLabel, botUserID string) (client.CommentDTO, bool) {
+	var zero client.CommentDTO
+
+	if d.Anchor.FileID == "" || d.IsResolved != nil && *d.IsResolved || len(d.Comments) == 0 {
+		return zero, false
+	}
+	if !HasDiscussionLabel(d, reviewedLabel) {
+		return zero, false
+	}
+
+	for _, c := range d.Comments {
+		if c.AuthorID != botUserID {
+			return zero, false
+		}
+	}
+
+	return d.Comments[len(d.Comments)-1], true
+}
+
+// ShouldAnswerMention is the "was the bot asked something?" predicate for discussions started by humans.
+// Returns the latest comment that mentions the bot and true when:
+//   - its first comment was not authored by the bot (bot threads are handled by ShouldReplyToDiscussion)
+//   - LatestUnansweredComment finds a comment matching isMention
+//   - the bot has authored fewer than maxPerThread comments in this thread
+func ShouldAnswerMention(d client.DiscussionInFileDTO, botUserID string, isMention func(text string) bool, maxPerThread int) (client.CommentDTO, bool) {
+	var zero client.CommentDTO
+
+	if len(d.Comments) == 0 || d.Comments[0].AuthorID == botUserID {
+		return zero, false
+	}
+
+	mention, ok := LatestUnansweredComment(d, botUserID, isMention)
+	if !ok {
+		return zero, false
+	}
+
+	var botCount int
+	for _, c := range d.Comments {
+		if c.AuthorID == botUserID {
+			botCount++
+		}
+	}
+	if maxPerThread > 0 && botCount >= maxPerThread {
+		return zero, false
+	}
+
+	return mention, true
+}
+
+// LatestUnansweredComment returns the latest human comment matching match that the bot has not
+// commented after, in a discussion that is not resolved.
+func LatestUnansweredComment(d client.DiscussionInFileDTO, botUserID string, match func(text string) bool) (client.CommentDTO, bool) {
+	var zero client.CommentDTO
+
+	if d.IsResolved != nil && *d.IsResolved {
+		return zero, false
+	}
+
+	latest := -1
+	for i, c := range d.Comments {
+		switch {
+		case c.AuthorID == botUserID:
+			latest = -1 // Everything before the bot's comment has been answered.
+		case match(c.Text):
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return zero, false
+	}
+
+	return d.Comments[latest], true
+}
+
 type CreateDiscussionRequest struct {
 	Review  *Review
 	Comment string
 	File    string
 	Line    int
+	// EndLine extends the anchor over the lines Line..EndLine; 0 anchors to Line only.
+	EndLine int
+	// OldSide anchors Line..EndLine on the base revision of the file, for comments on removed lines.
+	OldSide bool
 }
 
+// snippetSearchWindow is how many lines around the requested line are searched for the snippet.
+const snippetSearchWindow = 5
+
 const markdownMarkupType = "markdown"
 
-// CreateDiscussion creates a discussion for a given review, file, and line.
-func CreateDiscussion(ctx context.Context, upsourceClient *client.Client, reviewedLabel string, req CreateDiscussionRequest) error {
+// CreateDiscussion creates a discussion for a given review, file, and line and returns it.
+func CreateDiscussion(ctx context.Context, upsourceClient *client.Client, reviewedLabel string, req CreateDiscussionRequest) (*client.DiscussionInFileDTO, error) {
 	if req.File == "" { // No file specified, create a general discussion
-		_, err := upsourceClient.CreateDiscussion(ctx, client.CreateDiscussionRequestDTO{
+		return upsourceClient.CreateDiscussion(ctx, client.CreateDiscussionRequestDTO{
 			Anchor:     client.AnchorDTO{},
 			ReviewID:   &req.Review.review.ReviewID,
 			Text:       req.Comment,
@@ -122,55 +370,106 @@ func CreateDiscussion(ctx context.Context, upsourceClient *client.Client, review
 			MarkupType: markdownMarkupType,
 			Labels:     []client.LabelDTO{{Name: reviewedLabel}},
 		})
+	}
+
+	file, err := reviewFile(ctx, upsourceClient, req.Review, req.File, req.OldSide)
+	if err != nil {
+		return nil, err
+	}
 
-		return err
+	anchor, err := createAnchorForLines(ctx, upsourceClient, *file, req.Line, req.EndLine)
+	if err != nil {
+		return nil, fmt.Errorf("error creating anchor for line %d in file %s: %v", req.Line, req.File, err)
+	}
+
+	return upsourceClient.CreateDiscussion(ctx, client.CreateDiscussionRequestDTO{
+		Anchor:     *anchor,
+		ReviewID:   &req.Review.review.ReviewID,
+		Text:       req.Comment,
+		ProjectID:  req.Review.review.ReviewID.ProjectID,
+		MarkupType: markdownMarkupType,
+		Labels:     []client.LabelDTO{{Name: reviewedLabel}},
+	})
+}
+
+// LocateSnippet returns the line nearest to line, within snippetSearchWindow lines of a review file,
+// that contains the first line of snippet. The file is read at the head of the review, or at its base
+// revision for oldSide. It reports false when no nearby line contains the snippet.
+func LocateSnippet(ctx context.Context, upsourceClient *client.Client, review *Review, path string, line int, snippet string, oldSide bool) (int, bool, error) {
+	file, err := reviewFile(ctx, upsourceClient, review, path, oldSide)
+	if err != nil {
+		return 0, false, err
 	}
 
-	for _, fileDiffSummary := range req.Review.filesDiffSummary {
-		if fileDiffSummary.File.FileName != "/"+req.File && fileDiffSummary.File.FileName != req.File {
+	fileContent, err := upsourceClient.GetFileContent(ctx, *file)
+	if err != nil {
+		return 0, false, fmt.Errorf("error getting file content for %s: %v", file.FileName, err)
+	}
+
+	found, ok := relocateLineBySnippet(fileContent.FileContent.Text, line, snippet)
+	return found, ok, nil
+}
+
+// reviewFile returns a file changed in the review, at the head of the review or at its base revision for oldSide.
+func reviewFile(ctx context.Context, upsourceClient *client.Client, review *Review, path string, oldSide bool) (*client.FileInRevisionDTO, error) {
+	for _, fileDiffSummary := range review.filesDiffSummary {
+		if fileDiffSummary.File.FileName != "/"+path && fileDiffSummary.File.FileName != path {
 			continue // Skip files that are not the specified one
 		}
 
-		anchor, err := createAnchorForLine(ctx, upsourceClient, fileDiffSummary, req.Line)
+		if !oldSide {
+			file := fileDiffSummary.File
+			return &file, nil
+		}
+
+		baseFile, err := baseRevisionFile(ctx, upsourceClient, review, fileDiffSummary)
 		if err != nil {
-			return fmt.Errorf("error creating anchor for line %d in file %s: %v", req.Line, req.File, err)
+			return nil, fmt.Errorf("error getting base revision of file %s: %v", path, err)
 		}
+		return baseFile, nil
+	}
 
-		_, err = upsourceClient.CreateDiscussion(ctx, client.CreateDiscussionRequestDTO{
-			Anchor:     *anchor,
-			ReviewID:   &req.Review.review.ReviewID,
-			Text:       req.Comment,
-			ProjectID:  req.Review.review.ReviewID.ProjectID,
-			MarkupType: markdownMarkupType,
-			Labels:     []client.LabelDTO{{Name: reviewedLabel}},
-		})
+	return nil, fmt.Errorf("file %s not found in review %s", path, review.review.Title)
+}
 
-		return err
+// baseRevisionFile returns the file of the review diff on the base (left) side.
+func baseRevisionFile(ctx context.Context, upsourceClient *client.Client, review *Review, fileDiffSummary client.FileDiffSummaryDTO) (*client.FileInRevisionDTO, error) {
+	var t = true
+	diff, err := upsourceClient.GetFileInReviewSummaryDiff(ctx, client.FileInReviewDiffRequestDTO{
+		File: client.FileInReviewDTO{
+			ReviewID: review.review.ReviewID,
+			File:     fileDiffSummary.File,
+		},
+		Revisions: &client.RevisionsSetDTO{
+			SelectAll: &t,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if diff.LeftFile == nil {
+		return nil, fmt.Errorf("file %s has no base revision", fileDiffSummary.File.FileName)
 	}
 
-	return fmt.Errorf("file %s not found in review %s", req.File, req.Review.review.Title)
+	return diff.LeftFile, nil
 }
 
-// createAnchorForLine creates an anchor for a given line in a file.
-func createAnchorForLine(ctx context.Context, upsourceClient *client.Client, fileDiffSummary client.FileDiffSummaryDTO, line int) (*client.AnchorDTO, error) {
-	fileContent, err := upsourceClient.GetFileContent(ctx, client.FileInRevisionDTO{
-		ProjectID:  fileDiffSummary.File.ProjectID,
-		RevisionID: fileDiffSummary.File.RevisionID,
-		FileName:   fileDiffSummary.File.FileName,
-	})
+// createAnchorForLines creates an anchor spanning the lines line..endLine in a file.
+func createAnchorForLines(ctx context.Context, upsourceClient *client.Client, file client.FileInRevisionDTO, line, endLine int) (*client.AnchorDTO, error) {
+	fileContent, err := upsourceClient.GetFileContent(ctx, file)
 	if err != nil {
-		return nil, fmt.Errorf("error getting file content for %s: %v", fileDiffSummary.File.FileName, err)
+		return nil, fmt.Errorf("error getting file content for %s: %v", file.FileName, err)
 	}
 
-	text := fileContent.FileContent.Text
-	startOffset, endOffset, err := findRangeInFileContent(text, line)
+	startOffset, endOffset, err := findRangeForLines(fileContent.FileContent.Text, line, endLine)
 	if err != nil {
-		return nil, fmt.Errorf("error finding range for line %d in file %s: %v", line, fileDiffSummary.File.FileName, err)
+		return nil, fmt.Errorf("error finding range for line %d in file %s: %v", line, file.FileName, err)
 	}
 
 	return &client.AnchorDTO{
-		RevisionID: fileDiffSummary.File.RevisionID,
-		FileID:     fileDiffSummary.File.FileName,
+		RevisionID: file.RevisionID,
+		FileID:     file.FileName,
 		Range: &client.RangeDTO{
 			StartOffset: startOffset,
 			EndOffset:   endOffset,
@@ -205,3 +504,54 @@ func findRangeInFileContent(fileContent string, line int) (int32, int32, error)
 
 	return startOffset, endOffset, nil
 }
+
+// findRangeForLines finds the start and end offsets spanning the lines startLine..endLine in a file content.
+// When endLine is not after startLine or lies outside the file, the range covers startLine only.
+func findRangeForLines(fileContent string, startLine, endLine int) (int32, int32, error) {
+	startOffset, endOffset, err := findRangeInFileContent(fileContent, startLine)
+	if err != nil || endLine <= startLine {
+		return startOffset, endOffset, err
+	}
+
+	lines := strings.Split(fileContent, "\n")
+	if endLine > len(lines) {
+		return startOffset, endOffset, nil
+	}
+
+	for i := startLine; i < endLine; i++ {
+		endOffset += int32(utf8.RuneCount([]byte(lines[i]))) + 1 // +1 for the '\n'
+	}
+
+	return startOffset, endOffset, nil
+}
+
+// relocateLineBySnippet returns the line nearest to line, within snippetSearchWindow lines,
+// that contains the first line of snippet. Whitespace differences are ignored.
+// The line itself and false are returned when no nearby line matches.
+func relocateLineBySnippet(fileContent string, line int, snippet string) (int, bool) {
+	var target string
+	for _, snippetLine := range strings.Split(snippet, "\n") {
+		if target = strings.Join(strings.Fields(snippetLine), " "); target != "" {
+			break
+		}
+	}
+	if target == "" || line <= 0 {
+		return line, false
+	}
+
+	lines := strings.Split(fileContent, "\n")
+	matches := func(l int) bool {
+		return l >= 1 && l <= len(lines) && strings.Contains(strings.Join(strings.Fields(lines[l-1]), " "), target)
+	}
+
+	for delta := 0; delta <= snippetSearchWindow; delta++ {
+		if matches(line - delta) {
+			return line - delta, true
+		}
+		if matches(line + delta) {
+			return line + delta, true
+		}
+	}
+
+	return line, false
+}
@@ -1,6 +1,7 @@
 package upsource
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/groall/upsource-go-client/client"
@@ -111,6 +112,250 @@ func TestShouldReplyToDiscussion(t *testing.T) {
 	}
 }
 
+func TestShouldAnswerMention(t *testing.T) {
+	const botID = "bot-1"
+	resolved := true
+	isMention := func(text string) bool { return strings.Contains(text, "@bot") }
+
+	tests := []struct {
+		name        string
+		disc        client.DiscussionInFileDTO
+		maxPer      int
+		wantComment string
+	}{
+		{
+			name: "mention in human thread — answer",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: "human-a", Text: "Is this safe?"},
+				{CommentID: "c2", AuthorID: "human-b", Text: "@bot what do you think?"},
+				{CommentID: "c3", AuthorID: "human-a", Text: "Thanks"},
+			}},
+			maxPer:      3,
+			wantComment: "c2",
+		},
+		{
+			name: "bot already answered — skip",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: "human-a", Text: "@bot is this safe?"},
+				{CommentID: "c2", AuthorID: botID, Text: "Yes."},
+				{CommentID: "c3", AuthorID: "human-a", Text: "Thanks"},
+			}},
+			maxPer: 3,
+		},
+		{
+			name: "mentioned again after answer — answer",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: "human-a", Text: "@bot is this safe?"},
+				{CommentID: "c2", AuthorID: botID, Text: "Yes."},
+				{CommentID: "c3", AuthorID: "human-a", Text: "@bot and with nil?"},
+			}},
+			maxPer:      3,
+			wantComment: "c3",
+		},
+		{
+			name: "no mention — skip",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: "human-a", Text: "Is this safe?"},
+			}},
+			maxPer: 3,
+		},
+		{
+			name: "bot thread — skip",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: botID, Text: "Possible nil dereference."},
+				{CommentID: "c2", AuthorID: "human-a", Text: "@bot where?"},
+			}},
+			maxPer: 3,
+		},
+		{
+			name: "resolved — skip",
+			disc: client.DiscussionInFileDTO{
+				IsResolved: &resolved,
+				Comments:   []client.CommentDTO{{CommentID: "c1", AuthorID: "human-a", Text: "@bot?"}},
+			},
+			maxPer: 3,
+		},
+		{
+			name: "bot reached cap — skip",
+			disc: client.DiscussionInFileDTO{Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: "human-a", Text: "@bot?"},
+				{CommentID: "c2", AuthorID: botID, Text: "Yes."},
+				{CommentID: "c3", AuthorID: "human-a", Text: "@bot??"},
+			}},
+			maxPer: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment, ok := ShouldAnswerMention(tt.disc, botID, isMention, tt.maxPer)
+			if ok != (tt.wantComment != "") {
+				t.Fatalf("ShouldAnswerMention = %v, want %v", ok, tt.wantComment != "")
+			}
+			if comment.CommentID != tt.wantComment {
+				t.Fatalf("ShouldAnswerMention comment = %q, want %q", comment.CommentID, tt.wantComment)
+			}
+		})
+	}
+}
+
+func TestShouldRecheckDiscussion(t *testing.T) {
+	const botID = "bot-1"
+	resolved := true
+	labels := []client.LabelDTO{{Name: "ai-reviewed"}}
+	anchor := client.AnchorDTO{FileID: "/main.go"}
+
+	tests := []struct {
+		name        string
+		disc        client.DiscussionInFileDTO
+		wantComment string
+	}{
+		{
+			name: "unanswered bot thread — recheck",
+			disc: client.DiscussionInFileDTO{Anchor: anchor, Labels: labels, Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: botID, Text: "Possible nil dereference."},
+			}},
+			wantComment: "c1",
+		},
+		{
+			name: "only bot comments — recheck the last one",
+			disc: client.DiscussionInFileDTO{Anchor: anchor, Labels: labels, Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: botID, Text: "Possible nil dereference."},
+				{CommentID: "c2", AuthorID: botID, Text: "Still reproducible after the last push."},
+			}},
+			wantComment: "c2",
+		},
+		{
+			name: "human reply in between — skip",
+			disc: client.DiscussionInFileDTO{Anchor: anchor, Labels: labels, Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: botID, Text: "Possible nil dereference."},
+				{CommentID: "c2", AuthorID: "human-a", Text: "Where?"},
+				{CommentID: "c3", AuthorID: botID, Text: "On line 12."},
+			}},
+		},
+		{
+			name: "human has the last word — skip",
+			disc: client.DiscussionInFileDTO{Anchor: anchor, Labels: labels, Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: botID, Text: "Possible nil dereference."},
+				{CommentID: "c2", AuthorID: "human-a", Text: "Where?"},
+			}},
+		},
+		{
+			name: "human thread — skip",
+			disc: client.DiscussionInFileDTO{Anchor: anchor, Labels: labels, Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: "human-a", Text: "Why?"},
+				{CommentID: "c2", AuthorID: botID, Text: "Because."},
+			}},
+		},
+		{
+			name: "general discussion — skip",
+			disc: client.DiscussionInFileDTO{Labels: labels, Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: botID, Text: "Summary."},
+			}},
+		},
+		{
+			name: "resolved — skip",
+			disc: client.DiscussionInFileDTO{Anchor: anchor, Labels: labels, IsResolved: &resolved, Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: botID, Text: "Possible nil dereference."},
+			}},
+		},
+		{
+			name: "missing label — skip",
+			disc: client.DiscussionInFileDTO{Anchor: anchor, Comments: []client.CommentDTO{
+				{CommentID: "c1", AuthorID: botID, Text: "Possible nil dereference."},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment, ok := ShouldRecheckDiscussion(tt.disc, "ai-reviewed", botID)
+			if ok != (tt.wantComment != "") {
+				t.Fatalf("ShouldRecheckDiscussion = %v, want %v", ok, tt.wantComment != "")
+			}
+			if comment.CommentID != tt.wantComment {
+				t.Fatalf("ShouldRecheckDiscussion comment = %q, want %q", comment.CommentID, tt.wantComment)
+			}
+		})
+	}
+}
+
+func TestShouldEscalateDiscussion(t *testing.T) {
+	const botID = "bot-1"
+	resolved := true
+	labels := []client.LabelDTO{{Name: "ai-reviewed"}}
+	capped := []client.CommentDTO{
+		{CommentID: "c1", AuthorID: botID, Text: "Possible nil dereference."},
+		{CommentID: "c2", AuthorID: "human-a", Text: "It is never nil."},
+		{CommentID: "c3", AuthorID: botID, Text: "It is nil when the cache is cold."},
+		{CommentID: "c4", AuthorID: "human-a", Text: "No, the cache is warmed on start."},
+	}
+
+	tests := []struct {
+		name        string
+		disc        client.DiscussionInFileDTO
+		maxPer      int
+		wantComment string
+	}{
+		{
+			name:        "cap reached and human has the last word — escalate",
+			disc:        client.DiscussionInFileDTO{Labels: labels, Comments: capped},
+			maxPer:      2,
+			wantComment: "c4",
+		},
+		{
+			name:   "below cap — skip",
+			disc:   client.DiscussionInFileDTO{Labels: labels, Comments: capped},
+			maxPer: 3,
+		},
+		{
+			name:   "no cap — skip",
+			disc:   client.DiscussionInFileDTO{Labels: labels, Comments: capped},
+			maxPer: 0,
+		},
+		{
+			name:   "bot has the last word — skip",
+			disc:   client.DiscussionInFileDTO{Labels: labels, Comments: capped[:3]},
+			maxPer: 2,
+		},
+		{
+			name:   "already escalated — skip",
+			disc:   client.DiscussionInFileDTO{Labels: append(labels, client.LabelDTO{Name: "ai-escalated"}), Comments: capped},
+			maxPer: 2,
+		},
+		{
+			name: "escalation note posted but label missing — skip",
+			disc: client.DiscussionInFileDTO{Labels: labels, Comments: append(capped[:4:4],
+				client.CommentDTO{CommentID: "c5", AuthorID: botID, Text: EscalationNotePrefix + "Alice to decide."},
+				client.CommentDTO{CommentID: "c6", AuthorID: "human-a", Text: "Thanks."},
+			)},
+			maxPer: 2,
+		},
+		{
+			name:   "resolved — skip",
+			disc:   client.DiscussionInFileDTO{Labels: labels, IsResolved: &resolved, Comments: capped},
+			maxPer: 2,
+		},
+		{
+			name:   "missing label — skip",
+			disc:   client.DiscussionInFileDTO{Comments: capped},
+			maxPer: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment, ok := ShouldEscalateDiscussion(tt.disc, "ai-reviewed", "ai-escalated", botID, tt.maxPer)
+			if ok != (tt.wantComment != "") {
+				t.Fatalf("ShouldEscalateDiscussion = %v, want %v", ok, tt.wantComment != "")
+			}
+			if comment.CommentID != tt.wantComment {
+				t.Fatalf("ShouldEscalateDiscussion comment = %q, want %q", comment.CommentID, tt.wantComment)
+			}
+		})
+	}
+}
+
 func Test_findRangeInFileContent(t *testing.T) {
 	type args struct {
 		fileContent string
@@ -211,3 +456,81 @@ func Test_findRangeInFileContent(t *testing.T) {
 		})
 	}
 }
+
+func Test_findRangeForLines(t *testing.T) {
+	const fileContent = "line 1\nline 2\n\nline 4"
+
+	tests := []struct {
+		name      string
+		startLine int
+		endLine   int
+		wantStart int32
+		wantEnd   int32
+		wantErr   bool
+	}{
+		{name: "single line", startLine: 2, endLine: 0, wantStart: 7, wantEnd: 13},
+		{name: "range across an empty line", startLine: 2, endLine: 4, wantStart: 7, wantEnd: 21},
+		{name: "end line before start line", startLine: 2, endLine: 1, wantStart: 7, wantEnd: 13},
+		{name: "end line outside the file falls back to start line", startLine: 1, endLine: 9, wantStart: 0, wantEnd: 6},
+		{name: "empty start line", startLine: 3, endLine: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd, err := findRangeForLines(fileContent, tt.startLine, tt.endLine)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findRangeForLines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotStart != tt.wantStart || gotEnd != tt.wantEnd {
+				t.Errorf("findRangeForLines() = %d, %d, want %d, %d", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func Test_relocateLineBySnippet(t *testing.T) {
+	const fileContent = "func A() {\n\tx := 1\n\ty := x * 2\n\treturn y\n}"
+
+	tests := []struct {
+		name    string
+		line    int
+		snippet string
+		want    int
+		wantOK  bool
+	}{
+		{name: "line already matches", line: 3, snippet: "y := x * 2", want: 3, wantOK: true},
+		{name: "moves to nearby line", line: 1, snippet: "  y := x *  2", want: 3, wantOK: true},
+		{name: "uses first non-blank snippet line", line: 5, snippet: "\n\treturn y\n}", want: 4, wantOK: true},
+		{name: "keeps line without a match", line: 2, snippet: "z := 3", want: 2},
+		{name: "keeps line without a snippet", line: 2, snippet: "", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := relocateLineBySnippet(fileContent, tt.line, tt.snippet)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("relocateLineBySnippet() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_lineForOffset(t *testing.T) {
+	const text = "line 1\nлиния 2\nline 3"
+
+	tests := []struct {
+		offset int32
+		want   int
+	}{
+		{offset: 0, want: 1},
+		{offset: 6, want: 1},
+		{offset: 7, want: 2},
+		{offset: 15, want: 3},
+		{offset: 21, want: 3},
+		{offset: 22, want: 0},
+		{offset: -1, want: 0},
+	}
+	for _, tt := range tests {
+		if got := lineForOffset(text, tt.offset); got != tt.want {
+			t.Errorf("lineForOffset(%d) = %d, want %d", tt.offset, got, tt.want)
+		}
+	}
+}
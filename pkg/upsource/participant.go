@@ -0,0 +1,95 @@
+package upsource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/groall/upsource-go-client/client"
+)
+
+// GetParticipantState returns the state of a review participant and false when the user does not participate in the review.
+func (r *Review) GetParticipantState(userID string) (client.ParticipantStateEnum, bool) {
+	for _, p := range r.review.Participants {
+		if p.UserID != userID {
+			continue
+		}
+		if p.State == nil {
+			return client.ParticipantStateEnumUnread, true
+		}
+		return *p.State, true
+	}
+
+	return 0, false
+}
+
+// SetReviewerState adds the user to the review as a reviewer when it is not a participant yet
+// and sets its participant state, e.g. accepted or rejected.
+func SetReviewerState(ctx context.Context, upsourceClient *client.Client, review *Review, userID string, state client.ParticipantStateEnum) error {
+	current, ok := review.GetParticipantState(userID)
+	if ok && current == state {
+		return nil
+	}
+
+	if !ok {
+		if err := AddReviewer(ctx, upsourceClient, review, userID); err != nil {
+			return err
+		}
+	}
+
+	_, err := upsourceClient.UpdateParticipantInReview(ctx, client.UpdateParticipantInReviewRequestDTO{
+		ReviewID: review.review.ReviewID,
+		State:    state,
+		UserID:   userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update participant state: %w", err)
+	}
+
+	for i := range review.review.Participants {
+		if review.review.Participants[i].UserID == userID {
+			review.review.Participants[i].State = &state
+		}
+	}
+
+	return nil
+}
+
+// AddReviewer adds the user to the review as a reviewer unless it already participates in the review.
+func AddReviewer(ctx context.Context, upsourceClient *client.Client, review *Review, userID string) error {
+	if _, ok := review.GetParticipantState(userID); ok {
+		return nil
+	}
+
+	err := upsourceClient.AddParticipantToReview(ctx, client.ParticipantInReviewRequestDTO{
+		ReviewID: review.review.ReviewID,
+		Participant: client.ParticipantInReviewDTO{
+			UserID: userID,
+			Role:   client.Reviewer,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add reviewer: %w", err)
+	}
+	review.review.Participants = append(review.review.Participants, client.ParticipantInReviewDTO{UserID: userID, Role: client.Reviewer})
+
+	return nil
+}
+
+// GetFileOwners returns the Upsource owners of the files of the review, keyed by repository-relative path.
+// Files without an owner are left out.
+func GetFileOwners(ctx context.Context, upsourceClient *client.Client, review *Review) (map[string]string, error) {
+	res, err := upsourceClient.GetReviewOwnershipSummary(ctx, review.review.ReviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review ownership summary: %w", err)
+	}
+
+	owners := make(map[string]string, len(res.Files))
+	for _, f := range res.Files {
+		if f.UserID != "" {
+			owners[strings.TrimPrefix(f.FilePath, "/")] = f.UserID
+		}
+	}
+
+	return owners, nil
+}
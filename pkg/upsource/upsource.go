@@ -39,13 +39,78 @@ func (r *Review) GetReviewID() client.ReviewIdDTO {
 }
 
 func (r *Review) GetProjectID() string {
+	if r.review == nil {
+		return ""
+	}
 	return r.review.ReviewID.ProjectID
 }
 
 func (r *Review) GetTitle() string {
+	if r.review == nil {
+		return ""
+	}
 	return r.review.Title
 }
 
+// GetDescription returns the review description.
+func (r *Review) GetDescription() string {
+	if r.review == nil {
+		return ""
+	}
+	return r.review.Description
+}
+
+// GetReviewerIDs returns the Upsource user IDs of the participants with the reviewer role.
+func (r *Review) GetReviewerIDs() []string {
+	if r.review == nil {
+		return nil
+	}
+	var reviewers []string
+	for _, p := range r.review.Participants {
+		if p.Role == client.Reviewer {
+			reviewers = append(reviewers, p.UserID)
+		}
+	}
+	return reviewers
+}
+
+// GetIssueIDs returns the IDs of the issues linked to the review in Upsource.
+func (r *Review) GetIssueIDs() []string {
+	if r.review == nil {
+		return nil
+	}
+	issues := make([]string, 0, len(r.review.Issue))
+	for _, i := range r.review.Issue {
+		issues = append(issues, i.IssueID)
+	}
+	return issues
+}
+
+// NewReview wraps a review descriptor without loading the repository and the changes of the review.
+func NewReview(review *client.ReviewDescriptorDTO) *Review {
+	return &Review{review: review}
+}
+
+// GetAuthorID returns the Upsource user ID of the review creator.
+func (r *Review) GetAuthorID() string {
+	if r.review == nil {
+		return ""
+	}
+	return r.review.CreatedBy
+}
+
+// GetLabels returns the names of the review labels.
+func (r *Review) GetLabels() []string {
+	if r.review == nil {
+		return nil
+	}
+	labels := make([]string, 0, len(r.review.Labels))
+	for _, l := range r.review.Labels {
+		labels = append(labels, l.Name)
+	}
+	return labels
+}
+
 // ListReviewedReviews lists open reviews that already carry reviewedLabel.
 // Mirror of ListReviews used by the reply pass to find threads the bot may need to follow up on.
 func ListReviewedReviews(ctx context.Context, upsourceClient *client.Client, query string, reviewedLabel string) ([]*Review, error) {
@@ -89,6 +154,34 @@ func ListReviewedReviews(ctx context.Context, upsourceClient *client.Client, que
 	return reviewsToCheck, nil
 }
 
+// ListOpenReviews lists open reviews with a branch that match the given query. Only the review
+// descriptor is loaded, which is enough to list discussions; use LoadReview before reviewing.
+func ListOpenReviews(ctx context.Context, upsourceClient *client.Client, query string) ([]*Review, error) {
+	upsourceReviews, err := upsourceClient.GetReviews(ctx, client.ReviewsRequestDTO{
+		Limit: 10000,
+		Query: query,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upsourceReviews: %w", err)
+	}
+
+	var reviews []*Review
+	for _, review := range upsourceReviews.Reviews {
+		if review.State != client.ReviewStateEnumOpen || len(review.Branch) == 0 {
+			continue
+		}
+		review := review
+		reviews = append(reviews, &Review{review: &review, branch: review.Branch[0]})
+	}
+
+	return reviews, nil
+}
+
+// LoadReview loads the repository and changed files of a review returned by ListOpenReviews.
+func LoadReview(ctx context.Context, upsourceClient *client.Client, review *Review) (*Review, error) {
+	return newReviewFromUpsourceReview(ctx, *review.review, upsourceClient)
+}
+
 // ListReviews lists reviews in Upsource that match the given query.
 func ListReviews(ctx context.Context, upsourceClient *client.Client, query string, reviewedLabel string, invitationLabel string) ([]*Review, error) {
 	upsourceReviews, err := upsourceClient.GetReviews(ctx, client.ReviewsRequestDTO{
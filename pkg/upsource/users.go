@@ -0,0 +1,25 @@
+package upsource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/groall/upsource-go-client/client"
+)
+
+// GetUserNames returns the display names of the users with the given IDs. Unknown users are left out.
+func GetUserNames(ctx context.Context, upsourceClient *client.Client, userIDs []string) (map[string]string, error) {
+	res, err := upsourceClient.GetUserInfo(ctx, client.UserInfoRequestDTO{IDs: userIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	names := make(map[string]string, len(res.Infos))
+	for _, info := range res.Infos {
+		if info.Name != "" {
+			names[info.UserID] = info.Name
+		}
+	}
+
+	return names, nil
+}